@@ -0,0 +1,89 @@
+package context
+
+import (
+	stdContext "context"
+	"fmt"
+	"time"
+)
+
+// CanceledError ...
+type CanceledError struct{}
+
+func (err *CanceledError) Error() string {
+	return "Context canceled"
+}
+
+// Is allows to match this error with standard library context.Canceled
+func (err *CanceledError) Is(target error) bool {
+	return target == stdContext.Canceled
+}
+
+// DeadlineExceededError ...
+type DeadlineExceededError struct {
+	Deadline time.Time
+}
+
+func (err *DeadlineExceededError) Error() string {
+	return fmt.Sprintf("Context deadline %v exceeded", err.Deadline.Format(time.RFC3339Nano))
+}
+
+// Is allows to match this error with standard library context.DeadlineExceeded
+func (err *DeadlineExceededError) Is(target error) bool {
+	return target == stdContext.DeadlineExceeded
+}
+
+// CancelWithCause works as Cancel(), but also stores cause which would be available with Cause() for current and all child contexts
+func (context *ctx) CancelWithCause(cause error) {
+	if cause == nil {
+		cause = &CanceledError{}
+	}
+	if context.setCause(cause) { // repeated cancel does not change cause, so it is not reported again
+		context.notify(LifecycleCancelRequested, cause)
+	}
+	go func() {
+		context.cancel()
+	}()
+}
+
+// Err returns nil till context is not canceled, DeadlineExceededError if its deadline passed, otherwise CanceledError
+func (context *ctx) Err() error {
+	cause := context.Cause()
+	if cause == nil {
+		return nil
+	}
+	if deadlineExceeded, ok := cause.(*DeadlineExceededError); ok {
+		return deadlineExceeded
+	}
+	return &CanceledError{}
+}
+
+// Cause returns the first cause with which current or any parent context was canceled
+func (context *ctx) Cause() error {
+	context.causeMutex.Lock()
+	defer context.causeMutex.Unlock()
+	return context.cause
+}
+
+// only the first cause is stored, all next ones are ignored, returns true if cause is set by current call
+func (context *ctx) setCause(cause error) bool {
+	context.causeMutex.Lock()
+	defer context.causeMutex.Unlock()
+	if context.cause != nil {
+		return false
+	}
+	context.cause = cause
+	close(context.canceled)
+	return true
+}
+
+// Canceled returns channel what closes when cancellation starts, it is closed before Opened() which waits for childs closing
+func (context *ctx) Canceled() chan struct{} {
+	return context.canceled
+}
+
+func (context *ctx) recursiveSetCause(cause error) {
+	context.setCause(cause)
+	for _, child := range context.childs {
+		child.recursiveSetCause(cause)
+	}
+}
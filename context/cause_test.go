@@ -0,0 +1,75 @@
+package context_test
+
+import (
+	stdContext "context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_CancelWithCause(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	parent, err := root.NewContextFor(newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child, err := parent.NewContextFor(newNode(), "1", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if child.Err() != nil || child.Cause() != nil {
+		t.Fatal("not canceled context has error")
+	}
+
+	handlerCauses := make(chan error, 1)
+	child.SetOnBeforeClosing(func(current context.Context) {
+		handlerCauses <- current.Cause()
+	})
+
+	cause := errors.New("crashed")
+	parent.CancelWithCause(cause)
+
+	select {
+	case handlerCause := <-handlerCauses:
+		if handlerCause != cause {
+			t.Fatalf("handler obtained unexpected cause: %v", handlerCause)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("closing handler is not called")
+	}
+
+	<-parent.Opened()
+
+	if child.Cause() != cause {
+		t.Fatalf("cause is not propagated to child: %v", child.Cause())
+	}
+
+	if !errors.Is(child.Err(), stdContext.Canceled) {
+		t.Fatalf("unexpected child error: %v", child.Err())
+	}
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root canceled without cause returns %v", err)
+	}
+}
+
+func Test_RootWaitReturnsCause(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	if _, err := root.NewContextFor(newNode(), "0", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	cause := errors.New("crashed")
+	root.CancelWithCause(cause)
+
+	if err := root.Wait(); err != cause {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
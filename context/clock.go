@@ -0,0 +1,188 @@
+package context
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ClockTimer ...
+type ClockTimer interface {
+	Stop() bool // returns false if timer already fired or stopped
+}
+
+// Clock is a source of time for context deadlines, it could be taken by components with current.Clock()
+type Clock interface {
+	Now() time.Time
+	After(duration time.Duration) <-chan time.Time
+	AfterFunc(duration time.Duration, handler func()) ClockTimer
+}
+
+type realClock struct{}
+
+// NewRealClock returns clock based on time package
+func NewRealClock() Clock {
+	return &realClock{}
+}
+
+func (clock *realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (clock *realClock) After(duration time.Duration) <-chan time.Time {
+	return time.After(duration)
+}
+
+func (clock *realClock) AfterFunc(duration time.Duration, handler func()) ClockTimer {
+	return time.AfterFunc(duration, handler)
+}
+
+// WithClock replaces real clock for the whole context tree, it is intended for tests with FakeClock
+func WithClock(clock Clock) RootOption {
+	return func(options *rootOptions) {
+		options.clock = clock
+	}
+}
+
+// Clock returns clock of context tree
+func (context *ctx) Clock() Clock {
+	return context.tree.clock
+}
+
+// FakeClock changes its time only with Advance() or Set() calls, timers fire when their time is reached
+type FakeClock struct {
+	now    time.Time
+	timers []*fakeTimer
+	added  *sync.Cond // signaled when new timer is added
+	ready  sync.Mutex
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	channel  chan time.Time // nil for AfterFunc timers
+	handler  func()
+}
+
+// NewFakeClock ...
+func NewFakeClock(now time.Time) *FakeClock {
+	clock := &FakeClock{
+		now:    now,
+		timers: []*fakeTimer{},
+	}
+	clock.added = sync.NewCond(&clock.ready)
+	return clock
+}
+
+// Now ...
+func (clock *FakeClock) Now() time.Time {
+	clock.ready.Lock()
+	defer clock.ready.Unlock()
+	return clock.now
+}
+
+// After ...
+func (clock *FakeClock) After(duration time.Duration) <-chan time.Time {
+	timer := &fakeTimer{clock: clock, channel: make(chan time.Time, 1)}
+	clock.add(timer, duration)
+	return timer.channel
+}
+
+// AfterFunc calls handler in its own goroutine, like time.AfterFunc does
+func (clock *FakeClock) AfterFunc(duration time.Duration, handler func()) ClockTimer {
+	timer := &fakeTimer{clock: clock, handler: handler}
+	clock.add(timer, duration)
+	return timer
+}
+
+func (clock *FakeClock) add(timer *fakeTimer, duration time.Duration) {
+	clock.ready.Lock()
+	timer.deadline = clock.now.Add(duration)
+	clock.timers = append(clock.timers, timer)
+	clock.added.Broadcast()
+	clock.ready.Unlock()
+
+	if duration <= 0 {
+		clock.Advance(0)
+	}
+}
+
+// Stop ...
+func (timer *fakeTimer) Stop() bool {
+	timer.clock.ready.Lock()
+	defer timer.clock.ready.Unlock()
+	return timer.clock.remove(timer)
+}
+
+func (clock *FakeClock) remove(removed *fakeTimer) bool {
+	for i, timer := range clock.timers {
+		if timer == removed {
+			clock.timers = append(clock.timers[:i:i], clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Timers returns number of timers which are not fired or stopped yet
+func (clock *FakeClock) Timers() int {
+	clock.ready.Lock()
+	defer clock.ready.Unlock()
+	return len(clock.timers)
+}
+
+// WaitTimers blocks till clock has at least count not fired timers, so test could be sure that component is waiting before Advance() call
+func (clock *FakeClock) WaitTimers(count int) {
+	clock.ready.Lock()
+	defer clock.ready.Unlock()
+	for len(clock.timers) < count {
+		clock.added.Wait()
+	}
+}
+
+// Advance moves clock forward and fires all reached timers in order of their deadlines
+func (clock *FakeClock) Advance(duration time.Duration) {
+	clock.ready.Lock()
+	clock.now = clock.now.Add(duration)
+	clock.ready.Unlock()
+	clock.fire()
+}
+
+// Set moves clock to specified time, it could not be moved backward
+func (clock *FakeClock) Set(now time.Time) {
+	clock.ready.Lock()
+	if now.After(clock.now) {
+		clock.now = now
+	}
+	clock.ready.Unlock()
+	clock.fire()
+}
+
+func (clock *FakeClock) fire() {
+	clock.ready.Lock()
+
+	reached := []*fakeTimer{}
+	for _, timer := range clock.timers {
+		if !timer.deadline.After(clock.now) {
+			reached = append(reached, timer)
+		}
+	}
+	for _, timer := range reached {
+		clock.remove(timer)
+	}
+	now := clock.now
+
+	clock.ready.Unlock()
+
+	sort.SliceStable(reached, func(i int, j int) bool {
+		return reached[i].deadline.Before(reached[j].deadline)
+	})
+
+	for _, timer := range reached {
+		if timer.channel != nil {
+			timer.channel <- now
+		} else {
+			go timer.handler()
+		}
+	}
+}
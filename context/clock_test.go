@@ -0,0 +1,100 @@
+package context_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+// waitTimers fails test instead of hanging when expected timer is never added
+func waitTimers(t *testing.T, clock *context.FakeClock, count int) {
+	t.Helper()
+
+	added := make(chan struct{})
+	go func() {
+		clock.WaitTimers(count)
+		close(added)
+	}()
+
+	select {
+	case <-added:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("%v timers are not added, there are %v", count, clock.Timers())
+	}
+}
+
+func Test_FakeClockDeadline(t *testing.T) {
+	clock := context.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+
+	ctx, err := root.NewContextWithTimeout(time.Hour, newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if deadline, _ := ctx.Deadline(); !deadline.Equal(time.Date(2020, 1, 1, 1, 0, 0, 0, time.UTC)) {
+		t.Fatalf("deadline %v is not based on fake clock", deadline)
+	}
+
+	clock.Advance(59 * time.Minute)
+
+	select {
+	case <-ctx.Opened():
+		t.Fatal("context is closed before deadline")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	clock.Advance(time.Minute)
+
+	select {
+	case <-ctx.Opened():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context is not closed after fake clock passed deadline")
+	}
+
+	if _, ok := ctx.Err().(*context.DeadlineExceededError); !ok {
+		t.Fatalf("unexpected error: %v", ctx.Err())
+	}
+
+	root.Cancel()
+	root.Wait()
+}
+
+func Test_FakeClockTimersOrder(t *testing.T) {
+	clock := context.NewFakeClock(time.Unix(0, 0))
+
+	fired := make(chan int, 3)
+	clock.AfterFunc(3*time.Second, func() { fired <- 3 })
+	second := clock.After(2 * time.Second)
+	stopped := clock.AfterFunc(time.Second, func() { fired <- 1 })
+
+	waitTimers(t, clock, 3)
+	if !stopped.Stop() {
+		t.Fatal("timer is not stopped")
+	}
+
+	clock.Advance(2 * time.Second)
+
+	select {
+	case now := <-second:
+		if !now.Equal(time.Unix(2, 0)) {
+			t.Fatalf("unexpected time %v", now)
+		}
+	default:
+		t.Fatal("After() channel is not fired")
+	}
+
+	if clock.Timers() != 1 {
+		t.Fatalf("expected 1 pending timer, got %v", clock.Timers())
+	}
+
+	clock.Set(time.Unix(10, 0))
+
+	if value := <-fired; value != 3 {
+		t.Fatalf("unexpected timer %v fired", value)
+	}
+	if clock.Timers() != 0 {
+		t.Fatalf("expected no pending timers, got %v", clock.Timers())
+	}
+}
@@ -1,8 +1,10 @@
 package context
 
 import (
+	stdContext "context"
 	"fmt"
 	"sync"
+	"time"
 )
 
 // ParentContextAlreadyInClosingStateError ...
@@ -14,13 +16,45 @@ func (err *ParentContextAlreadyInClosingStateError) Error() string {
 
 // Context ...
 type Context interface {
-	NewContextFor(instance ContextedInstance, componentName string, componentType string) (Context, error) // create new child context
-	SetOnBeforeClosing(handler func(Context))                                                              // this handler calls for current context before closing all child and subchild contexts
-	Opened() chan struct{}                                                                                 // channel what closes when all childs are closed and you can close current context
-	Cancel()                                                                                               // sends signal to current and all child contexts to close hierarchy gracefully (childs first, parent second)
-	Log(arguments ...interface{})                                                                          // log context even
-	log(objects []interface{})
+	NewContextFor(instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error)                                // create new child context, options are applied before it is started
+	NewContextForStd(std stdContext.Context, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error)     // create new child context which would be canceled when std context is done
+	NewContextWithDeadline(deadline time.Time, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error)   // create new child context which would be canceled when deadline passes
+	NewPassiveContext(componentName string, componentType string) (Context, error)                                                                                // create new child context without goroutine, it participates in closing order and hooks, but has no instance loop
+	NewContextWithTimeout(timeout time.Duration, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) // create new child context which would be canceled after timeout
+	Deadline() (time.Time, bool)                                                                                                                                  // returns the earliest deadline of current context and its parents, false if there is no deadline
+	Clock() Clock                                                                                                                                                 // clock of context tree which is used for deadlines, components should use it instead of time package to be testable with FakeClock
+	AddOnBeforeClosing(handler func(Context)) func()                                                                                                              // registers one more handler which is called before closing childs (the last added is called first), returns function which removes it
+	AddOnClosed(handler func(Context)) func()                                                                                                                     // registers handler which is called after current context and all its childs are closed (the last added is called first), returns function which removes it
+	SetOnBeforeClosing(handler func(Context))                                                                                                                     // this handler calls for current context before closing all child and subchild contexts (replaces previously set one), cancellation reason is available with Cause()
+	Done() chan struct{}                                                                                                                                          // channel what closes only after instance Go() returned
+	ID() int64                                                                                                                                                    // context identifier, unique among siblings
+	Path() []DebugNode                                                                                                                                            // nodes from root to current context
+	Parent() Context                                                                                                                                              // parent context, nil for root
+	Children() []Context                                                                                                                                          // child contexts which are not closed and detached yet, sorted by ID
+	State() NodeState                                                                                                                                             // running, closing or closed
+	Opened() chan struct{}                                                                                                                                        // channel what closes when all childs are closed and you can close current context
+	Canceled() chan struct{}                                                                                                                                      // channel what closes when current context cancellation starts (before its childs are closed)
+	Cancel()                                                                                                                                                      // sends signal to current and all child contexts to close hierarchy gracefully (childs first, parent second)
+	CancelWithCause(cause error)                                                                                                                                  // same as Cancel(), but with cause which would be available for current and all child contexts
+	Err() error                                                                                                                                                   // nil till context is not canceled, otherwise CanceledError or DeadlineExceededError
+	Cause() error                                                                                                                                                 // the first cause with which current or any parent context was canceled
+	SetGracePeriod(gracePeriod time.Duration)                                                                                                                     // limits time for current context loop to exit after Opened() is closed, after it context is reported and force-detached from parent (inherited by childs)
+	SetClosingOrder(order int)                                                                                                                                    // siblings with lower order are closed first (for example, listeners before database pools), default is 0, siblings with the same order are closed one by one in order of creation
+	SetFailurePolicy(policy FailurePolicy)                                                                                                                        // defines what happens when current context fails (panics or exits with unclosed childs), by default failure is propagated to parent
+	SetRestartPolicy(minDelay time.Duration, maxDelay time.Duration, maxRestarts int)                                                                             // restart delay for FailurePolicyRestart starts from minDelay and doubles for each next restart till maxDelay, after maxRestarts restarts parent fails with TooManyRestartsError (0 is unlimited)
+	LogEvent(level Level, message string, fields ...Field)                                                                                                        // log context event with level, message and key/value fields
+	Log(arguments ...interface{})                                                                                                                                 // log context even
+	WithValue(key interface{}, value interface{}) Context                                                                                                         // stores value in current context and returns it
+	Value(key interface{}) interface{}                                                                                                                            // returns value stored in current or the nearest parent context
+	Std() stdContext.Context                                                                                                                                      // standard library context what is done when current context Opened() channel closes
+	log(event *Event)
 	wait()
+	fail(cause error)
+	snapshot() *NodeSnapshot
+	addObserver(observer Observer, queueSize int) func()
+	metrics() *Metrics
+	shutdownReport(completed bool, duration time.Duration) *ShutdownReport
+	setLoggedValues(keys []interface{})
 }
 
 // ContextedInstance ...
@@ -31,7 +65,25 @@ type ContextedInstance interface {
 type tree struct {
 	changesAllowed sync.Mutex
 	closingAllowed sync.Mutex
-	debugger       Debugger
+	debugger       StructuredDebugger
+
+	loggedKeys      []interface{}
+	loggedKeysMutex sync.Mutex
+
+	stuck      []*StuckNode // contexts which were force-detached after grace period
+	stuckMutex sync.Mutex
+
+	metrics   *treeMetrics
+	observers observers
+	tracer    Tracer // nil if tracing is disabled
+
+	goroutineLabels []string // nil if goroutines are not labeled
+
+	clock Clock
+
+	concurrentClosing bool // siblings with the same closing order are closed simultaneously instead of one by one
+
+	closed chan struct{} // closes when root closing is finished and observers are closed
 }
 
 type ctx struct {
@@ -44,19 +96,48 @@ type ctx struct {
 	childsWaitGroup       sync.WaitGroup
 	loopWaitGroup         sync.WaitGroup
 	opened                chan struct{}
+	done                  chan struct{} // closes when instance loop exits
 	tree                  *tree
+	startedAt             time.Time
 
 	closed      bool
+	finished    bool // instance loop exited
 	closedMutex sync.Mutex
 
-	onBeforeClosing      func(current Context)
-	onBeforeClosingMutex sync.Mutex
+	parentReleased sync.Once
+
+	gracePeriod   time.Duration // zero if closing is not limited
+	closingOrder  int
+	shutdownMutex sync.Mutex
+
+	deadline      time.Time     // zero if current context and all it parents have no deadline
+	timeout       time.Duration // non zero if context was created with timeout, restarted context counts its deadline from it again
+	deadlineTimer ClockTimer
+
+	cause      error         // nil till context is not canceled
+	canceled   chan struct{} // closed when cause is set
+	causeMutex sync.Mutex
+
+	failurePolicy FailurePolicy
+	restartPolicy *restartPolicy // shared by all restarts of the same instance, nil till it is set or the first restart
+	failure       error          // nil till context is not failed by itself or by propagated failure of its child
+	failureMutex  sync.Mutex
+
+	onBeforeClosing     hooks
+	onBeforeClosingHook *hook // handler set with SetOnBeforeClosing
+	onClosed            hooks
+	hooksMutex          sync.Mutex
+
+	values      map[interface{}]interface{}
+	valuesMutex sync.Mutex
+
+	span Span // nil if tracing is disabled
 
 	debuggerNodePath []DebugNode // it is not a pointer, it is full array copy
 	debuggerMutex    sync.Mutex
 }
 
-func newContextFor(instance ContextedInstance, debugger Debugger) (Context, error) {
+func newContextFor(instance ContextedInstance, debugger Debugger, options *rootOptions) (Context, error) {
 
 	newContext := &ctx{
 		id:                    0,
@@ -67,11 +148,19 @@ func newContextFor(instance ContextedInstance, debugger Debugger) (Context, erro
 		instance:              instance,
 		childsCreatingAllowed: true,
 		opened:                make(chan struct{}),
-		tree:                  &tree{debugger: debugger},
-		onBeforeClosing:       func(current Context) {},
+		canceled:              make(chan struct{}),
+		done:                  make(chan struct{}),
+		tree:                  &tree{debugger: newStructuredDebugger(debugger), metrics: newTreeMetrics(), tracer: options.tracer, goroutineLabels: options.goroutineLabels, clock: options.clock, concurrentClosing: options.concurrentClosing, closed: make(chan struct{})},
+		startedAt:             time.Now(),
 		closed:                false,
 	}
 
+	for _, observer := range options.observers {
+		newContext.addObserver(observer.observer, observer.queueSize)
+	}
+
+	newContext.countCreated()
+	newContext.notify(LifecycleNodeCreated, nil)
 	newContext.start()
 
 	return newContext, nil
@@ -82,53 +171,82 @@ func (context *ctx) Opened() chan struct{} {
 }
 
 func (context *ctx) recursiveSetChildsCreatingAllowed(value bool) {
-	context.Log(103, "recursiveSetChildsCreatingAllowed", "...")
+	context.LogEvent(levelTraceClosing, "recursiveSetChildsCreatingAllowed ...")
 	for _, child := range context.childs {
 		child.recursiveSetChildsCreatingAllowed(value)
 	}
 	context.childsCreatingAllowed = value
-	context.Log(103, "recursiveSetChildsCreatingAllowed", "done")
+	context.LogEvent(levelTraceClosing, "recursiveSetChildsCreatingAllowed done")
 }
 
 func (context *ctx) close() {
-	context.Log(105, "close", "...")
+	context.LogEvent(levelTraceClose, "close ...")
 	context.closedMutex.Lock()
 	defer context.closedMutex.Unlock()
 
 	if !context.closed {
-		context.Log(105, "close", "channel closed")
+		if context.deadlineTimer != nil { // stopped before channel is closed, so closed context never has pending timer
+			context.deadlineTimer.Stop()
+		}
+		context.LogEvent(levelTraceClose, "close channel closed")
 		close(context.opened)
 		context.closed = true
 	}
-	context.Log(105, "close", "done")
+	context.LogEvent(levelTraceClose, "close done")
+}
+
+func (context *ctx) isClosed() bool {
+	context.closedMutex.Lock()
+	defer context.closedMutex.Unlock()
+	return context.closed
 }
 
 func (context *ctx) recursiveClosing() {
-	context.Log(103, "recursiveClosing", "...")
-	context.callOnCloseHandler()
+	if context.isClosed() { // context and all its childs are already closed
+		return
+	}
 
-	childs := make(map[int64]*ctx)
+	context.LogEvent(levelTraceClosing, "recursiveClosing ...")
+	closingStartedAt := context.tree.clock.Now()
+	context.notify(LifecycleBeforeClosing, context.Cause())
+	context.callOnBeforeClosingHandlers()
 
-	for id, child := range context.childs {
-		childs[id] = child
-	}
+	childs := []*ctx{}
 
-	for id, child := range childs {
-		child.recursiveClosing()
-		delete(context.childs, id)
+	context.tree.changesAllowed.Lock()
+	for _, child := range context.childs {
+		childs = append(childs, child)
 	}
+	context.tree.changesAllowed.Unlock()
+
+	context.closeChilds(childs)
 
-	context.Log(104, "childsWaitGroup", "...")
+	context.LogEvent(levelTraceWaitGroups, "childsWaitGroup ...")
 	context.childsWaitGroup.Wait()
-	context.Log(104, "childsWaitGroup", "done")
+	context.LogEvent(levelTraceWaitGroups, "childsWaitGroup done")
 
 	context.close()
 
-	context.Log(104, "loopWaitGroup", "...")
-	context.loopWaitGroup.Wait()
-	context.Log(104, "loopWaitGroup", "done")
+	context.LogEvent(levelTraceWaitGroups, "loopWaitGroup ...")
+	context.waitLoop()
+	context.LogEvent(levelTraceWaitGroups, "loopWaitGroup done")
+
+	context.observeClosingDuration(context.tree.clock.Now().Sub(closingStartedAt))
+
+	if context.isPassive() {
+		context.finishPassive()
+	}
+
+	context.callOnClosedHandlers()
+
+	context.notify(LifecycleNodeClosed, context.Cause())
 
-	context.Log(103, "recursiveClosing", "done")
+	if context.parent == nil { // nothing would be observed after root is closed
+		context.closeObservers()
+		close(context.tree.closed)
+	}
+
+	context.LogEvent(levelTraceClosing, "recursiveClosing done")
 }
 
 func (context *ctx) cancel() {
@@ -136,29 +254,51 @@ func (context *ctx) cancel() {
 	defer context.tree.closingAllowed.Unlock()
 
 	{
-		context.Log(102, "cancel", "recursiveSetChildsCreatingAllowed ...")
+		context.LogEvent(levelTraceCancel, "cancel recursiveSetChildsCreatingAllowed ...")
 		context.tree.changesAllowed.Lock()
 		context.recursiveSetChildsCreatingAllowed(false)
+		context.recursiveSetCause(context.Cause())
 		context.tree.changesAllowed.Unlock()
-		context.Log(102, "cancel", "recursiveSetChildsCreatingAllowed done")
+		context.LogEvent(levelTraceCancel, "cancel recursiveSetChildsCreatingAllowed done")
 	}
 
 	{
-		context.Log(102, "cancel", "recursiveClosing ...")
+		context.LogEvent(levelTraceCancel, "cancel recursiveClosing ...")
 		context.recursiveClosing()
-		context.Log(102, "cancel", "recursiveClosing done")
+		context.LogEvent(levelTraceCancel, "cancel recursiveClosing done")
 	}
 
 }
 
 func (context *ctx) Cancel() {
-	go func() {
-		context.cancel()
-	}()
+	context.CancelWithCause(&CanceledError{})
 }
 
 // StartNewFor ...
-func (context *ctx) NewContextFor(instance ContextedInstance, componentName string, componentType string) (Context, error) {
+func (context *ctx) NewContextFor(instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) {
+	return context.newChildContextFor(instance, componentName, componentType, newChildOptions(options))
+}
+
+// ChildOption is applied to child context before its goroutine is started, so it is already in effect when instance Go() is called
+type ChildOption func(options *childOptions)
+
+func newChildOptions(options []ChildOption) childOptions {
+	result := childOptions{}
+	for _, option := range options {
+		option(&result)
+	}
+	return result
+}
+
+// childOptions are set before child goroutine is started
+type childOptions struct {
+	deadline      time.Time     // own deadline of child, zero if it inherits deadline from parent only
+	timeout       time.Duration // own timeout of child from which deadline was counted
+	failurePolicy FailurePolicy
+	restartPolicy *restartPolicy
+}
+
+func (context *ctx) newChildContextFor(instance ContextedInstance, componentName string, componentType string, options childOptions) (Context, error) {
 
 	// attach to parent new child
 	parent := context
@@ -181,90 +321,119 @@ func (context *ctx) NewContextFor(instance ContextedInstance, componentName stri
 		instance:              instance,
 		childsCreatingAllowed: parent.childsCreatingAllowed,
 		opened:                make(chan struct{}),
+		canceled:              make(chan struct{}),
+		done:                  make(chan struct{}),
 		tree:                  parent.tree,
-		onBeforeClosing:       func(current Context) {},
+		startedAt:             time.Now(),
 		closed:                false,
+		deadline:              earliestDeadline(parent.deadline, options.deadline),
+		timeout:               options.timeout,
+		failurePolicy:         options.failurePolicy,
+		restartPolicy:         options.restartPolicy,
+		gracePeriod:           parent.getGracePeriod(),
 	}
 
 	if parent.childsCreatingAllowed {
 		parent.childs[parent.nextChildID] = newContext
 		parent.nextChildID++
 		parent.childsWaitGroup.Add(1)
-		newContext.start()
+		newContext.countCreated()
+		newContext.notify(LifecycleNodeCreated, nil)
+		if newContext.isPassive() {
+			newContext.startPassive()
+		} else {
+			newContext.start()
+		}
+		newContext.startDeadlineTimer(options.deadline)
 		return newContext, nil
 	}
 	return nil, &ParentContextAlreadyInClosingStateError{}
 }
 
 func (context *ctx) wait() {
-	context.Log(101, "waiting till childs finished")
+	context.LogEvent(LevelTrace, "waiting till childs finished")
 	context.childsWaitGroup.Wait()
-	context.Log(101, "waiting till loop finished")
+	context.LogEvent(LevelTrace, "waiting till loop finished")
 	context.loopWaitGroup.Wait()
-	context.Log(101, "waiting done")
+	<-context.tree.closed
+	context.LogEvent(LevelTrace, "waiting done")
 }
 
 func (context *ctx) Log(arguments ...interface{}) {
+	context.log(NewEventFromObjects(arguments))
+}
 
-	objects := make([]interface{}, 0)
+// LogEvent ...
+func (context *ctx) LogEvent(level Level, message string, fields ...Field) {
+	context.log(NewEvent(level, message, fields...))
+}
 
-	for _, argument := range arguments {
-		objects = append(objects, argument)
+func (context *ctx) log(event *Event) {
+	if loggedValues := context.loggedValues(); len(loggedValues) > 0 {
+		event.Fields = append(loggedValues, event.Fields...)
 	}
 
-	context.log(objects)
-}
-
-func (context *ctx) log(objects []interface{}) {
 	context.debuggerMutex.Lock()
-	context.tree.debugger.Log(context.debuggerNodePath, objects)
+	context.tree.debugger.LogEvent(context.debuggerNodePath, event)
 	context.debuggerMutex.Unlock()
 }
 
 func (context *ctx) start() {
 
 	context.loopWaitGroup.Add(1)
+	context.startSpan()
 
 	go func(ctx *ctx) {
 
-		ctx.Log(100, "started")
+		ctx.setGoroutineLabels()
+		ctx.LogEvent(LevelDebug, "started")
+		ctx.notify(LifecycleGoStarted, nil)
+
+		var failure error
 
 		{ // wait till context execution would be finished, only after that you can dispose all context resources, otherwise it could try to create new child context on disposed resources
-			ctx.instance.Go(ctx)
-			ctx.Log(100, "finished")
+			failure = ctx.run()
+			ctx.setFinished()
+			ctx.countClosed()
+			ctx.notify(LifecycleGoFinished, ctx.Cause())
+			ctx.LogEvent(LevelDebug, "finished")
 		}
 
-		{ // panic on not closed childs
-			if len(ctx.childs) != 0 {
-				ctx.debuggerMutex.Lock()
-				node := ctx.debuggerNodePath[len(ctx.debuggerNodePath)-1]
-				panic(fmt.Sprintf("you tries to exit from context %v[%v] that have unclosed childs. Use context.Close() method, instead just exiting from goroutine!", node.ComponentType, node.ComponentName))
+		{ // fail on panic or not closed childs
+			if failure == nil {
+				failure = ctx.checkUnclosedChilds()
+			}
+
+			if failure != nil {
+				ctx.fail(failure)
+			}
+
+			if ctx.getFailure() != nil {
+				<-ctx.opened // wait till failed context would be closed with all its childs
+
+				if ctx.parent != nil && ctx.getFailurePolicy() == FailurePolicyRestart {
+					ctx.restart()
+				}
 			}
 		}
 
-		{ // childs WaitGroup decremented
-			if ctx.parent != nil {
-				ctx.parent.childsWaitGroup.Done()
+		{ // closed context is not a part of tree anymore
+			if ctx.parent != nil && ctx.isClosed() {
+				ctx.detachFromParent()
 			}
 		}
 
+		{ // span ends with cancellation cause
+			ctx.endSpan()
+		}
+
+		{ // childs WaitGroup decremented
+			ctx.releaseParent()
+		}
+
 		{ // loop finished
 			ctx.loopWaitGroup.Done()
 		}
 
 	}(context)
 }
-
-func (context *ctx) SetOnBeforeClosing(handler func(current Context)) {
-	context.onBeforeClosingMutex.Lock()
-	defer context.onBeforeClosingMutex.Unlock()
-	context.onBeforeClosing = handler
-}
-
-func (context *ctx) callOnCloseHandler() {
-	context.onBeforeClosingMutex.Lock()
-	defer context.onBeforeClosingMutex.Unlock()
-	if context.onBeforeClosing != nil {
-		context.onBeforeClosing(context)
-	}
-}
@@ -0,0 +1,82 @@
+// Package contexttest helps to check that components do not leave context nodes or goroutines behind after root context is closed
+package contexttest
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+// RootLabel is a goroutine label which distinguishes goroutines of different test roots
+const RootLabel = "contexttest.root"
+
+// recorder queue is big enough to keep all events of test tree, dropped events are reported as error
+const observerQueueSize = 1 << 16
+
+var nextRootID int64
+
+// Root is a root context which records logged and lifecycle events of all its nodes and labels their goroutines
+type Root struct {
+	context.RootContext
+	Recorder *Recorder
+	id       string
+	t        testing.TB
+}
+
+// NewRoot ...
+func NewRoot(t testing.TB, options ...context.RootOption) *Root {
+	id := fmt.Sprintf("%v", atomic.AddInt64(&nextRootID, 1))
+	recorder := NewRecorder()
+
+	options = append(options, context.WithGoroutineLabels(map[string]string{RootLabel: id}), context.WithObserver(recorder, observerQueueSize))
+
+	return &Root{
+		RootContext: context.NewRootContext(recorder, options...),
+		Recorder:    recorder,
+		id:          id,
+		t:           t,
+	}
+}
+
+// CancelAndVerify cancels root context and fails test if tree is not closed within timeout, some node finished before its childs or left goroutines
+func (root *Root) CancelAndVerify(timeout time.Duration) {
+	root.t.Helper()
+
+	report := root.Shutdown(timeout)
+
+	deadline := time.Now().Add(timeout)
+
+	if report.Completed { // observer receives events in its own goroutine
+		select {
+		case <-root.Recorder.RootClosed():
+		case <-time.After(time.Until(deadline)):
+		}
+	}
+
+	goroutines := labeledGoroutines(RootLabel, root.id)
+	for len(goroutines) > 0 && time.Now().Before(deadline) { // goroutines could still finish their exit
+		time.Sleep(10 * time.Millisecond)
+		goroutines = labeledGoroutines(RootLabel, root.id)
+	}
+
+	events := root.Recorder.LifecycleEvents()
+	for _, event := range events {
+		if event.Dropped > 0 {
+			root.t.Errorf("%v lifecycle events were dropped, tree could not be verified", event.Dropped)
+			return
+		}
+	}
+
+	tree := buildTree(events)
+	if tree == nil {
+		return
+	}
+	tree.setGoroutines(goroutines)
+
+	if !report.Completed || len(report.Stuck) > 0 || tree.hasProblems() {
+		root.t.Errorf("context tree is not closed correctly:\n%v", tree.diff())
+	}
+}
@@ -0,0 +1,139 @@
+package contexttest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+	"github.com/mcfly722/goPackages/context/contexttest"
+)
+
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, arguments ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, arguments...))
+}
+
+type node struct{}
+
+func (node *node) Go(current context.Context) {
+	<-current.Opened()
+}
+
+type leakingNode struct {
+	release chan struct{}
+}
+
+func (node *leakingNode) Go(current context.Context) {
+	go func() {
+		<-node.release // goroutine is not stopped on closing
+	}()
+	<-current.Opened()
+}
+
+type hungNode struct {
+	release chan struct{}
+}
+
+func (node *hungNode) Go(current context.Context) {
+	<-current.Opened()
+	<-node.release
+}
+
+// logs the same messages which context logs for its loop, tree is verified only with lifecycle events
+type misleadingNode struct {
+	release chan struct{}
+}
+
+func (node *misleadingNode) Go(current context.Context) {
+	<-current.Opened()
+	current.LogEvent(context.LevelDebug, "finished")
+	<-node.release
+}
+
+func Test_ClosedTree(t *testing.T) {
+	recording := &recordingT{TB: t}
+	root := contexttest.NewRoot(recording)
+
+	parent, err := root.NewContextFor(&node{}, "parent", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parent.NewContextFor(&node{}, "child", "node"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parent.NewPassiveContext("passive", "passive"); err != nil {
+		t.Fatal(err)
+	}
+
+	root.CancelAndVerify(5 * time.Second)
+
+	if len(recording.errors) != 0 {
+		t.Fatalf("unexpected errors: %v", recording.errors)
+	}
+}
+
+func Test_LeakedGoroutine(t *testing.T) {
+	recording := &recordingT{TB: t}
+	root := contexttest.NewRoot(recording)
+
+	leaking := &leakingNode{release: make(chan struct{})}
+	defer close(leaking.release)
+
+	parent, err := root.NewContextFor(&node{}, "parent", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parent.NewContextFor(leaking, "leaking", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	root.CancelAndVerify(200 * time.Millisecond)
+
+	if len(recording.errors) != 1 || !strings.Contains(recording.errors[0], "+     leaking[0] (node) 1 goroutines left\n") {
+		t.Fatalf("unexpected errors: %v", recording.errors)
+	}
+}
+
+func Test_HungNode(t *testing.T) {
+	recording := &recordingT{TB: t}
+	root := contexttest.NewRoot(recording)
+
+	hung := &hungNode{release: make(chan struct{})}
+	defer close(hung.release)
+
+	if _, err := root.NewContextFor(hung, "hung", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	root.CancelAndVerify(200 * time.Millisecond)
+
+	if len(recording.errors) != 1 || !strings.Contains(recording.errors[0], "+   hung[0] (node) not finished, 1 goroutines left\n") {
+		t.Fatalf("unexpected errors: %v", recording.errors)
+	}
+}
+
+func Test_LoggedMessagesAreIgnored(t *testing.T) {
+	recording := &recordingT{TB: t}
+	root := contexttest.NewRoot(recording)
+
+	misleading := &misleadingNode{release: make(chan struct{})}
+	defer close(misleading.release)
+
+	if _, err := root.NewContextFor(misleading, "misleading", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	root.CancelAndVerify(200 * time.Millisecond)
+
+	if len(recording.errors) != 1 || !strings.Contains(recording.errors[0], "+   misleading[0] (node) not finished, 1 goroutines left\n") {
+		t.Fatalf("unexpected errors: %v", recording.errors)
+	}
+}
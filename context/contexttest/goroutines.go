@@ -0,0 +1,67 @@
+package contexttest
+
+import (
+	"bytes"
+	"regexp"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+var labelsExpression = regexp.MustCompile(`^# labels: (\{.*\})$`)
+
+// labeledGoroutines returns count of goroutines per context path which have rootLabel with rootID value
+func labeledGoroutines(rootLabel string, rootID string) map[string]int {
+	buffer := &bytes.Buffer{}
+	pprof.Lookup("goroutine").WriteTo(buffer, 1)
+
+	goroutines := make(map[string]int)
+
+	// profile consists of blocks separated with empty line, each one starts with "<count> @ <pcs>" line
+	for _, block := range strings.Split(buffer.String(), "\n\n") {
+		lines := strings.Split(block, "\n")
+		if len(lines) < 2 {
+			continue
+		}
+
+		count, err := strconv.Atoi(strings.SplitN(lines[0], " ", 2)[0])
+		if err != nil {
+			continue
+		}
+
+		match := labelsExpression.FindStringSubmatch(lines[1])
+		if match == nil {
+			continue
+		}
+
+		labels := parseLabels(match[1])
+		if labels[rootLabel] != rootID {
+			continue
+		}
+
+		goroutines[labels[context.GoroutinePathLabel]] += count
+	}
+
+	return goroutines
+}
+
+var labelExpression = regexp.MustCompile(`("(?:[^"\\]|\\.)*"):("(?:[^"\\]|\\.)*")`)
+
+// labels are printed as {"key":"value", "key2":"value2"} with quoted keys and values
+func parseLabels(text string) map[string]string {
+	labels := make(map[string]string)
+	for _, match := range labelExpression.FindAllStringSubmatch(text, -1) {
+		key, err := strconv.Unquote(match[1])
+		if err != nil {
+			continue
+		}
+		value, err := strconv.Unquote(match[2])
+		if err != nil {
+			continue
+		}
+		labels[key] = value
+	}
+	return labels
+}
@@ -0,0 +1,79 @@
+package contexttest
+
+import (
+	"sync"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+// Record is an event logged by context node
+type Record struct {
+	Path  []context.DebugNode
+	Event *context.Event
+}
+
+// Recorder is a debugger and observer which keeps all logged and lifecycle events of context tree in order of their sending
+type Recorder struct {
+	records    []*Record
+	lifecycle  []*context.LifecycleEvent
+	rootClosed chan struct{} // closed when closed event of root context is received, it is the last lifecycle event of tree
+	ready      sync.Mutex
+}
+
+// NewRecorder ...
+func NewRecorder() *Recorder {
+	return &Recorder{
+		records:    []*Record{},
+		lifecycle:  []*context.LifecycleEvent{},
+		rootClosed: make(chan struct{}),
+	}
+}
+
+// Log ...
+func (recorder *Recorder) Log(nodePath []context.DebugNode, objects []interface{}) {
+	recorder.LogEvent(nodePath, context.NewEventFromObjects(objects))
+}
+
+// LogEvent ...
+func (recorder *Recorder) LogEvent(nodePath []context.DebugNode, event *context.Event) {
+	path := make([]context.DebugNode, len(nodePath))
+	copy(path, nodePath)
+
+	recorder.ready.Lock()
+	defer recorder.ready.Unlock()
+	recorder.records = append(recorder.records, &Record{Path: path, Event: event})
+}
+
+// Records returns copy of records list
+func (recorder *Recorder) Records() []*Record {
+	recorder.ready.Lock()
+	defer recorder.ready.Unlock()
+	records := make([]*Record, len(recorder.records))
+	copy(records, recorder.records)
+	return records
+}
+
+// OnEvent ...
+func (recorder *Recorder) OnEvent(event *context.LifecycleEvent) {
+	recorder.ready.Lock()
+	defer recorder.ready.Unlock()
+	recorder.lifecycle = append(recorder.lifecycle, event)
+
+	if event.Type == context.LifecycleNodeClosed && len(event.Path) == 1 {
+		close(recorder.rootClosed)
+	}
+}
+
+// LifecycleEvents returns copy of lifecycle events list
+func (recorder *Recorder) LifecycleEvents() []*context.LifecycleEvent {
+	recorder.ready.Lock()
+	defer recorder.ready.Unlock()
+	events := make([]*context.LifecycleEvent, len(recorder.lifecycle))
+	copy(events, recorder.lifecycle)
+	return events
+}
+
+// RootClosed is closed when closed event of root context is received, so all lifecycle events are recorded
+func (recorder *Recorder) RootClosed() <-chan struct{} {
+	return recorder.rootClosed
+}
@@ -0,0 +1,140 @@
+package contexttest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+type treeNode struct {
+	path       string
+	debugNode  context.DebugNode
+	childs     map[int64]*treeNode
+	started    bool // Go() was called, passive contexts are never started
+	finishedAt int  // index of finished event (closed event for passive context), -1 if node is not finished
+	goroutines int  // goroutines which are still running with label of this node
+}
+
+func newTreeNode(path string, debugNode context.DebugNode) *treeNode {
+	return &treeNode{
+		path:       path,
+		debugNode:  debugNode,
+		childs:     make(map[int64]*treeNode),
+		finishedAt: -1,
+	}
+}
+
+// builds tree of all nodes from their lifecycle events
+func buildTree(events []*context.LifecycleEvent) *treeNode {
+	var root *treeNode
+
+	for index, event := range events {
+		if len(event.Path) == 0 {
+			continue
+		}
+
+		if root == nil {
+			root = newTreeNode(context.PathString(event.Path[:1]), event.Path[0])
+		}
+
+		current := root
+		for i := 1; i < len(event.Path); i++ {
+			child, found := current.childs[event.Path[i].ID]
+			if !found {
+				child = newTreeNode(context.PathString(event.Path[:i+1]), event.Path[i])
+				current.childs[event.Path[i].ID] = child
+			}
+			current = child
+		}
+
+		switch event.Type {
+		case context.LifecycleGoStarted:
+			current.started = true
+		case context.LifecycleGoFinished:
+			current.finishedAt = index
+		case context.LifecycleNodeClosed:
+			if !current.started { // passive context has no loop, it is finished when it is closed
+				current.finishedAt = index
+			}
+		}
+	}
+
+	return root
+}
+
+func (node *treeNode) sortedChilds() []*treeNode {
+	childs := []*treeNode{}
+	for _, child := range node.childs {
+		childs = append(childs, child)
+	}
+	sort.Slice(childs, func(i int, j int) bool {
+		return childs[i].debugNode.ID < childs[j].debugNode.ID
+	})
+	return childs
+}
+
+func (node *treeNode) setGoroutines(goroutines map[string]int) {
+	node.goroutines = goroutines[node.path]
+	for _, child := range node.childs {
+		child.setGoroutines(goroutines)
+	}
+}
+
+// problems returns what is wrong with current node, empty list if node is closed correctly
+func (node *treeNode) problems() []string {
+	problems := []string{}
+
+	if node.finishedAt < 0 {
+		problems = append(problems, "not finished")
+	}
+
+	if node.finishedAt >= 0 {
+		for _, child := range node.childs {
+			if child.finishedAt > node.finishedAt {
+				problems = append(problems, fmt.Sprintf("finished before child %v[%v]", child.debugNode.ComponentName, child.debugNode.ID))
+			}
+		}
+	}
+
+	if node.goroutines > 0 {
+		problems = append(problems, fmt.Sprintf("%v goroutines left", node.goroutines))
+	}
+
+	return problems
+}
+
+func (node *treeNode) hasProblems() bool {
+	if len(node.problems()) > 0 {
+		return true
+	}
+	for _, child := range node.childs {
+		if child.hasProblems() {
+			return true
+		}
+	}
+	return false
+}
+
+// diff renders tree where each node is expected to be finished after its childs without goroutines left, wrong nodes are shown with -expected and +actual lines
+func (node *treeNode) diff() string {
+	builder := &strings.Builder{}
+	node.writeDiff(builder, 0)
+	return builder.String()
+}
+
+func (node *treeNode) writeDiff(builder *strings.Builder, depth int) {
+	title := fmt.Sprintf("%v%v[%v] (%v)", strings.Repeat("  ", depth), node.debugNode.ComponentName, node.debugNode.ID, node.debugNode.ComponentType)
+
+	if problems := node.problems(); len(problems) > 0 {
+		builder.WriteString(fmt.Sprintf("- %v finished\n", title))
+		builder.WriteString(fmt.Sprintf("+ %v %v\n", title, strings.Join(problems, ", ")))
+	} else {
+		builder.WriteString(fmt.Sprintf("  %v finished\n", title))
+	}
+
+	for _, child := range node.sortedChilds() {
+		child.writeDiff(builder, depth+1)
+	}
+}
@@ -0,0 +1,62 @@
+package context
+
+import (
+	"time"
+)
+
+// NewContextWithDeadline creates new child context which would be canceled with all its childs when deadline passes
+func (context *ctx) NewContextWithDeadline(deadline time.Time, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) {
+	childOptions := newChildOptions(options)
+	childOptions.deadline = deadline
+	return context.newChildContextFor(instance, componentName, componentType, childOptions)
+}
+
+// NewContextWithTimeout creates new child context which would be canceled with all its childs after timeout
+func (context *ctx) NewContextWithTimeout(timeout time.Duration, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) {
+	childOptions := newChildOptions(options)
+	childOptions.deadline = context.tree.clock.Now().Add(timeout)
+	childOptions.timeout = timeout
+	return context.newChildContextFor(instance, componentName, componentType, childOptions)
+}
+
+// Deadline ...
+func (context *ctx) Deadline() (time.Time, bool) {
+	return context.deadline, !context.deadline.IsZero()
+}
+
+func earliestDeadline(first time.Time, second time.Time) time.Time {
+	if first.IsZero() {
+		return second
+	}
+	if second.IsZero() || first.Before(second) {
+		return first
+	}
+	return second
+}
+
+// timer starts only for own deadline, inherited one would be handled by parent which owns it
+func (context *ctx) startDeadlineTimer(deadline time.Time) {
+	if deadline.IsZero() || !deadline.Equal(context.deadline) {
+		return
+	}
+
+	if context.parent != nil && context.parent.deadline.Equal(deadline) {
+		return
+	}
+
+	context.closedMutex.Lock()
+	defer context.closedMutex.Unlock()
+
+	if !context.closed {
+		context.deadlineTimer = context.tree.clock.AfterFunc(deadline.Sub(context.tree.clock.Now()), context.deadlineExceeded)
+	}
+}
+
+func (context *ctx) deadlineExceeded() {
+	context.LogEvent(LevelInfo, "deadline exceeded", NewField("deadline", context.deadline.Format(time.RFC3339Nano)))
+	cause := &DeadlineExceededError{Deadline: context.deadline}
+	if context.setCause(cause) {
+		context.notify(LifecycleCancelRequested, cause)
+	}
+	context.cancel()
+}
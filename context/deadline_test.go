@@ -0,0 +1,67 @@
+package context_test
+
+import (
+	stdContext "context"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_Timeout(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	ctx, err := root.NewContextWithTimeout(100*time.Millisecond, newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Opened():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context is not closed after timeout")
+	}
+
+	if ctx.Std().Err() != stdContext.DeadlineExceeded {
+		t.Fatalf("unexpected std context error: %v", ctx.Std().Err())
+	}
+
+	root.Cancel()
+	root.Wait()
+}
+
+func Test_DeadlineInheritance(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	if _, ok := root.Std().Deadline(); ok {
+		t.Fatal("root context has deadline")
+	}
+
+	deadline := time.Now().Add(time.Hour)
+
+	parent, err := root.NewContextWithDeadline(deadline, newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child, err := parent.NewContextFor(newNode(), "1", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if childDeadline, ok := child.Deadline(); !ok || !childDeadline.Equal(deadline) {
+		t.Fatalf("child deadline %v not inherited from parent %v", childDeadline, deadline)
+	}
+
+	laterChild, err := parent.NewContextWithTimeout(2*time.Hour, newNode(), "2", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if laterDeadline, _ := laterChild.Deadline(); !laterDeadline.Equal(deadline) {
+		t.Fatalf("child deadline %v is later than parent %v", laterDeadline, deadline)
+	}
+
+	root.Cancel()
+	root.Wait()
+}
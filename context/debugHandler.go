@@ -0,0 +1,41 @@
+package context
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type debugHandler struct {
+	root RootContext
+}
+
+// NewDebugHandler returns http handler which renders current context tree, format is selected with query parameter ?format=json|text|dot|svg (json by default)
+func NewDebugHandler(root RootContext) http.Handler {
+	return &debugHandler{root: root}
+}
+
+func (handler *debugHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	snapshot := handler.root.Snapshot()
+
+	switch format := request.URL.Query().Get("format"); format {
+	case "", "json":
+		data, err := snapshot.ToJSON()
+		if err != nil {
+			http.Error(response, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		response.Header().Set("Content-Type", "application/json")
+		response.Write(data)
+	case "text":
+		response.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		response.Write([]byte(snapshot.ToText()))
+	case "dot":
+		response.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		response.Write([]byte(snapshot.ToDOT()))
+	case "svg":
+		response.Header().Set("Content-Type", "image/svg+xml")
+		response.Write([]byte(snapshot.ToSVG()))
+	default:
+		http.Error(response, fmt.Sprintf("unknown format %q, use json, text, dot or svg", format), http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,64 @@
+package context_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_DebugHandler(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	parent, err := root.NewContextFor(newNode(), "parent", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parent.NewContextFor(newNode(), "child", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(context.NewDebugHandler(root))
+	defer server.Close()
+
+	formats := map[string]string{
+		"json": `"componentName":"child"`,
+		"text": "    child[0] (node) running",
+		"dot":  `"0.0" -> "0.0.0";`,
+		"svg":  "<svg",
+	}
+
+	for format, expected := range formats {
+		response, err := http.Get(server.URL + "?format=" + format)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := ioutil.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("%v format does not contain %v:\n%v", format, expected, string(body))
+		}
+	}
+
+	response, err := http.Get(server.URL + "?format=unknown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusBadRequest {
+		t.Fatalf("unknown format returned %v", response.StatusCode)
+	}
+
+	root.Cancel()
+	root.Wait()
+}
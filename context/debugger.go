@@ -2,7 +2,9 @@ package context
 
 import (
 	"fmt"
+	"path"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -13,17 +15,170 @@ type DebugNode struct {
 	ComponentName string
 }
 
+// Level of debugger event, lower value is more important
+type Level int
+
+const (
+	// LevelError ...
+	LevelError Level = 1
+	// LevelWarning ...
+	LevelWarning Level = 10
+	// LevelInfo ...
+	LevelInfo Level = 50
+	// LevelDebug ...
+	LevelDebug Level = 100
+	// LevelTrace ...
+	LevelTrace Level = 101
+)
+
+// levels of context internal trace events, they keep numbers of previous Log() calls, so existing maximum log levels filter them as before
+const (
+	levelTraceCancel     Level = 102
+	levelTraceClosing    Level = 103
+	levelTraceWaitGroups Level = 104
+	levelTraceClose      Level = 105
+)
+
+// String returns name of the nearest named level which is not less important than current
+func (level Level) String() string {
+	switch {
+	case level <= LevelError:
+		return "ERROR"
+	case level <= LevelWarning:
+		return "WARNING"
+	case level <= LevelInfo:
+		return "INFO"
+	case level <= LevelDebug:
+		return "DEBUG"
+	default:
+		return "TRACE"
+	}
+}
+
+// Field ...
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// NewField ...
+func NewField(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Event ...
+type Event struct {
+	Time      time.Time
+	Level     Level
+	Message   string
+	Fields    []Field
+	Unleveled bool // legacy Log() call without level, it has LevelInfo, but it is not filtered by level as before
+	Legacy    bool // legacy Log() call, its level is a verbosity of previous versions, not an importance
+}
+
+// NewEvent ...
+func NewEvent(level Level, message string, fields ...Field) *Event {
+	return &Event{
+		Time:    time.Now(),
+		Level:   level,
+		Message: message,
+		Fields:  fields,
+	}
+}
+
+// NewEventFromObjects converts variadic Log() arguments to event, first integer argument is a level (it is kept as is), all others are joined to message with commas
+func NewEventFromObjects(arguments []interface{}) *Event {
+	level := LevelInfo
+	unleveled := true
+
+	if len(arguments) > 0 {
+		if debugLevel, ok := arguments[0].(int); ok {
+			level = Level(debugLevel)
+			unleveled = false
+			arguments = arguments[1:]
+		}
+	}
+
+	vars := []string{}
+	for _, argument := range arguments {
+		vars = append(vars, fmt.Sprintf("%v", argument))
+	}
+
+	event := NewEvent(level, strings.Join(vars, ","))
+	event.Unleveled = unleveled
+	event.Legacy = true
+	return event
+}
+
+// severity returns level which is used to choose event importance, legacy verbosity levels are never more important than LevelInfo
+func (event *Event) severity() Level {
+	if event.Legacy && event.Level < LevelInfo {
+		return LevelInfo
+	}
+	return event.Level
+}
+
+// toObjects converts event to objects list for not structured debuggers (level is the first one, it is omitted for unleveled events)
+func (event *Event) toObjects() []interface{} {
+	objects := []interface{}{int(event.Level), event.Message}
+	if event.Unleveled {
+		objects = []interface{}{event.Message}
+	}
+	for _, field := range event.Fields {
+		objects = append(objects, fmt.Sprintf("%v=%v", field.Key, field.Value))
+	}
+	return objects
+}
+
+// FieldsString returns fields as space separated key=value pairs
+func (event *Event) FieldsString() string {
+	fields := []string{}
+	for _, field := range event.Fields {
+		fields = append(fields, fmt.Sprintf("%v=%v", field.Key, field.Value))
+	}
+	return strings.Join(fields, " ")
+}
+
 // Debugger ...
 type Debugger interface {
 	Log(nodePath []DebugNode, objects []interface{})
 }
 
+// StructuredDebugger receives events with level, message and fields instead of objects list
+type StructuredDebugger interface {
+	Debugger
+	LogEvent(nodePath []DebugNode, event *Event)
+}
+
+// objectsDebugger adapts not structured debugger to receive events
+type objectsDebugger struct {
+	debugger Debugger
+}
+
+func (adapter *objectsDebugger) Log(nodePath []DebugNode, objects []interface{}) {
+	adapter.debugger.Log(nodePath, objects)
+}
+
+func (adapter *objectsDebugger) LogEvent(nodePath []DebugNode, event *Event) {
+	adapter.debugger.Log(nodePath, event.toObjects())
+}
+
+func newStructuredDebugger(debugger Debugger) StructuredDebugger {
+	if structured, ok := debugger.(StructuredDebugger); ok {
+		return structured
+	}
+	return &objectsDebugger{debugger: debugger}
+}
+
 // EmptyDebugger ...
 type EmptyDebugger struct{}
 
 // Log ...
 func (emptyDebugger *EmptyDebugger) Log(nodePath []DebugNode, objects []interface{}) {}
 
+// LogEvent ...
+func (emptyDebugger *EmptyDebugger) LogEvent(nodePath []DebugNode, event *Event) {}
+
 // NewEmptyDebugger ...
 func NewEmptyDebugger() Debugger {
 	return &EmptyDebugger{}
@@ -33,18 +188,131 @@ func NewEmptyDebugger() Debugger {
 type ConsoleLogDebugger struct {
 	maximumLogLevel int
 	showIDs         bool
+	rules           []*logLevelRule // in order of registration
+	ready           sync.Mutex
+}
+
+type logLevelRule struct {
+	pattern         string
+	segments        []string
+	maximumLogLevel int
 }
 
 // NewConsoleLogDebugger ...
-func NewConsoleLogDebugger(maximumLogLevel int, showIDs bool) Debugger {
+func NewConsoleLogDebugger(maximumLogLevel int, showIDs bool) *ConsoleLogDebugger {
 	return &ConsoleLogDebugger{
 		maximumLogLevel: maximumLogLevel,
 		showIDs:         showIDs,
+		rules:           []*logLevelRule{},
+	}
+}
+
+// SetDefaultLogLevel changes maximum log level for nodes which are not matched by any rule
+func (consoleLogDebugger *ConsoleLogDebugger) SetDefaultLogLevel(maximumLogLevel int) {
+	consoleLogDebugger.ready.Lock()
+	defer consoleLogDebugger.ready.Unlock()
+	consoleLogDebugger.maximumLogLevel = maximumLogLevel
+}
+
+// SetLogLevel sets maximum log level for subtrees matched by pattern like "root->plugins->*".
+// Each pattern segment is matched (with path.Match syntax) against component name or type. The longest matched pattern wins,
+// among patterns with same length the one with more segments equal to component names wins, then the earliest registered one.
+// Setting level for already registered pattern keeps its registration order.
+func (consoleLogDebugger *ConsoleLogDebugger) SetLogLevel(pattern string, maximumLogLevel int) {
+	consoleLogDebugger.ready.Lock()
+	defer consoleLogDebugger.ready.Unlock()
+
+	for _, rule := range consoleLogDebugger.rules {
+		if rule.pattern == pattern {
+			rule.maximumLogLevel = maximumLogLevel
+			return
+		}
+	}
+
+	consoleLogDebugger.rules = append(consoleLogDebugger.rules, &logLevelRule{
+		pattern:         pattern,
+		segments:        strings.Split(pattern, "->"),
+		maximumLogLevel: maximumLogLevel,
+	})
+}
+
+// RemoveLogLevel removes rule previously set with SetLogLevel
+func (consoleLogDebugger *ConsoleLogDebugger) RemoveLogLevel(pattern string) {
+	consoleLogDebugger.ready.Lock()
+	defer consoleLogDebugger.ready.Unlock()
+
+	for i, rule := range consoleLogDebugger.rules {
+		if rule.pattern == pattern {
+			consoleLogDebugger.rules = append(consoleLogDebugger.rules[:i:i], consoleLogDebugger.rules[i+1:]...)
+			return
+		}
 	}
 }
 
+// MaximumLogLevelFor returns maximum log level for node
+func (consoleLogDebugger *ConsoleLogDebugger) MaximumLogLevelFor(nodePath []DebugNode) int {
+	consoleLogDebugger.ready.Lock()
+	defer consoleLogDebugger.ready.Unlock()
+
+	maximumLogLevel := consoleLogDebugger.maximumLogLevel
+	matchedSegments := 0
+	exactSegments := 0
+
+	for _, rule := range consoleLogDebugger.rules {
+		if len(rule.segments) < matchedSegments || !rule.matches(nodePath) {
+			continue
+		}
+
+		exact := rule.exactSegments(nodePath)
+		if len(rule.segments) > matchedSegments || exact > exactSegments { // with equal length and exactness the earlier rule is kept
+			maximumLogLevel = rule.maximumLogLevel
+			matchedSegments = len(rule.segments)
+			exactSegments = exact
+		}
+	}
+
+	return maximumLogLevel
+}
+
+// number of pattern segments which are equal to component names without wildcards
+func (rule *logLevelRule) exactSegments(nodePath []DebugNode) int {
+	exact := 0
+	for i, segment := range rule.segments {
+		if segment == nodePath[i].ComponentName {
+			exact++
+		}
+	}
+	return exact
+}
+
+// rule matches node and all its childs if pattern matches beginning of node path
+func (rule *logLevelRule) matches(nodePath []DebugNode) bool {
+	if len(rule.segments) > len(nodePath) {
+		return false
+	}
+
+	for i, segment := range rule.segments {
+		nameMatched, _ := path.Match(segment, nodePath[i].ComponentName)
+		typeMatched, _ := path.Match(segment, nodePath[i].ComponentType)
+		if !nameMatched && !typeMatched {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Log ...
 func (consoleLogDebugger *ConsoleLogDebugger) Log(nodePath []DebugNode, objects []interface{}) {
+	consoleLogDebugger.LogEvent(nodePath, NewEventFromObjects(objects))
+}
+
+// LogEvent ...
+func (consoleLogDebugger *ConsoleLogDebugger) LogEvent(nodePath []DebugNode, event *Event) {
+	if !event.Unleveled && consoleLogDebugger.MaximumLogLevelFor(nodePath) < int(event.Level) {
+		return
+	}
+
 	pathStrings := []string{}
 
 	for _, node := range nodePath {
@@ -57,28 +325,10 @@ func (consoleLogDebugger *ConsoleLogDebugger) Log(nodePath []DebugNode, objects
 
 	path := strings.Join(pathStrings, "->")
 
-	values := []string{}
-	values = append(values, fmt.Sprintf("%v\t", time.Now().Format(time.RFC3339)))
-
-	values = append(values, path)
-
-	valuesStr := strings.Join(values, "")
-
-	skipMessages := false
-	if len(objects) > 0 {
-		if debugLevel, ok := objects[0].(int); ok {
-			if consoleLogDebugger.maximumLogLevel < debugLevel {
-				skipMessages = true
-			}
-		}
+	message := event.Message
+	if len(event.Fields) > 0 {
+		message = fmt.Sprintf("%v\t%v", message, event.FieldsString())
 	}
 
-	if !skipMessages {
-		vars := []string{}
-		for _, object := range objects {
-			vars = append(vars, fmt.Sprintf("%v", object))
-		}
-		varsStr := strings.Join(vars, ",")
-		fmt.Println(fmt.Sprintf("%v\t%v", valuesStr, varsStr))
-	}
+	fmt.Println(fmt.Sprintf("%v\t%v\t%v\t%v", event.Time.Format(time.RFC3339), path, event.severity(), message))
 }
@@ -0,0 +1,252 @@
+package context_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+type recordingDebugger struct {
+	events []*context.Event
+	ready  sync.Mutex
+}
+
+func (debugger *recordingDebugger) Log(nodePath []context.DebugNode, objects []interface{}) {}
+
+func (debugger *recordingDebugger) LogEvent(nodePath []context.DebugNode, event *context.Event) {
+	debugger.ready.Lock()
+	defer debugger.ready.Unlock()
+	debugger.events = append(debugger.events, event)
+}
+
+func (debugger *recordingDebugger) find(message string) *context.Event {
+	debugger.ready.Lock()
+	defer debugger.ready.Unlock()
+	for _, event := range debugger.events {
+		if event.Message == message {
+			return event
+		}
+	}
+	return nil
+}
+
+type objectsDebugger struct {
+	objects [][]interface{}
+	ready   sync.Mutex
+}
+
+func (debugger *objectsDebugger) Log(nodePath []context.DebugNode, objects []interface{}) {
+	debugger.ready.Lock()
+	defer debugger.ready.Unlock()
+	debugger.objects = append(debugger.objects, objects)
+}
+
+func Test_StructuredDebugger(t *testing.T) {
+	debugger := &recordingDebugger{}
+	root := context.NewRootContext(debugger)
+
+	root.LogEvent(context.LevelWarning, "structured", context.NewField("key", "value"))
+	root.Log(2, "legacy", "message")
+
+	structured := debugger.find("structured")
+	if structured == nil || structured.Level != context.LevelWarning || structured.Fields[0].Key != "key" || structured.Fields[0].Value != "value" {
+		t.Fatalf("unexpected structured event: %+v", structured)
+	}
+
+	legacy := debugger.find("legacy,message")
+	if legacy == nil || legacy.Level != context.Level(2) || legacy.Level.String() != "WARNING" {
+		t.Fatalf("unexpected legacy event: %+v", legacy)
+	}
+
+	root.Cancel()
+	root.Wait()
+}
+
+func Test_ObjectsDebugger(t *testing.T) {
+	debugger := &objectsDebugger{}
+	root := context.NewRootContext(debugger)
+
+	root.LogEvent(context.LevelInfo, "structured", context.NewField("key", "value"))
+
+	root.Cancel()
+	root.Wait()
+
+	debugger.ready.Lock()
+	defer debugger.ready.Unlock()
+
+	for _, objects := range debugger.objects {
+		if len(objects) == 3 && objects[0] == int(context.LevelInfo) && objects[1] == "structured" && objects[2] == "key=value" {
+			return
+		}
+	}
+	t.Fatalf("objects debugger does not receive structured event: %v", debugger.objects)
+}
+
+func Test_ConsoleLogDebuggerLogLevels(t *testing.T) {
+	debugger := context.NewConsoleLogDebugger(50, true)
+	debugger.SetLogLevel("root->plugins->*", 110)
+	debugger.SetLogLevel("root->plugins->*->process", 10)
+
+	root := context.DebugNode{ID: 0, ComponentType: "root", ComponentName: "root"}
+	plugins := context.DebugNode{ID: 0, ComponentType: "pluginsManager", ComponentName: "plugins"}
+	plugin := context.DebugNode{ID: 3, ComponentType: "definition", ComponentName: "plugin.js"}
+	process := context.DebugNode{ID: 0, ComponentType: "process", ComponentName: "ping"}
+	engine := context.DebugNode{ID: 1, ComponentType: "eventLoop", ComponentName: "jsEngine"}
+
+	cases := []struct {
+		nodePath []context.DebugNode
+		expected int
+	}{
+		{[]context.DebugNode{root}, 50},
+		{[]context.DebugNode{root, plugins}, 50},
+		{[]context.DebugNode{root, plugins, plugin}, 110},
+		{[]context.DebugNode{root, plugins, plugin, engine}, 110},
+		{[]context.DebugNode{root, plugins, plugin, process}, 10},
+		{[]context.DebugNode{root, engine}, 50},
+	}
+
+	for _, c := range cases {
+		if level := debugger.MaximumLogLevelFor(c.nodePath); level != c.expected {
+			t.Fatalf("%+v has log level %v instead of %v", c.nodePath, level, c.expected)
+		}
+	}
+
+	debugger.RemoveLogLevel("root->plugins->*")
+	debugger.SetDefaultLogLevel(100)
+
+	if level := debugger.MaximumLogLevelFor([]context.DebugNode{root, plugins, plugin}); level != 100 {
+		t.Fatalf("removed rule is still applied, level=%v", level)
+	}
+}
+
+func Test_ConsoleLogDebuggerEqualLengthRules(t *testing.T) {
+	root := context.DebugNode{ID: 0, ComponentType: "root", ComponentName: "root"}
+	plugins := context.DebugNode{ID: 0, ComponentType: "pluginsManager", ComponentName: "plugins"}
+	plugin := context.DebugNode{ID: 3, ComponentType: "definition", ComponentName: "plugin.js"}
+	nodePath := []context.DebugNode{root, plugins, plugin}
+
+	for i := 0; i < 100; i++ { // rules were stored in map, so result depended on iteration order
+		debugger := context.NewConsoleLogDebugger(50, true)
+		debugger.SetLogLevel("root->plugins->*", 110)
+		debugger.SetLogLevel("root->*->plugin.js", 120)
+		debugger.SetLogLevel("root->pluginsManager->definition", 130)
+
+		if level := debugger.MaximumLogLevelFor(nodePath); level != 110 {
+			t.Fatalf("earliest registered rule is not applied, level=%v", level)
+		}
+
+		debugger.SetLogLevel("root->plugins->plugin.js", 10)
+
+		if level := debugger.MaximumLogLevelFor(nodePath); level != 10 {
+			t.Fatalf("exact name rule is not applied, level=%v", level)
+		}
+
+		debugger.SetLogLevel("root->plugins->*", 100) // level is changed, but registration order is kept
+		debugger.RemoveLogLevel("root->plugins->plugin.js")
+
+		if level := debugger.MaximumLogLevelFor(nodePath); level != 100 {
+			t.Fatalf("changed rule is not applied, level=%v", level)
+		}
+	}
+}
+
+func Test_LegacyLogForms(t *testing.T) {
+	debugger := &recordingDebugger{}
+	root := context.NewRootContext(debugger)
+
+	root.Log("without", "level")
+	root.Log(104, "old", "trace")
+	root.Log("5", "string is not a level")
+
+	cases := []struct {
+		message   string
+		level     context.Level
+		unleveled bool
+	}{
+		{"without,level", context.LevelInfo, true},
+		{"old,trace", context.Level(104), false},
+		{"5,string is not a level", context.LevelInfo, true},
+	}
+
+	for _, c := range cases {
+		event := debugger.find(c.message)
+		if event == nil || event.Level != c.level || event.Unleveled != c.unleveled {
+			t.Fatalf("unexpected event for %v: %+v", c.message, event)
+		}
+	}
+
+	root.Cancel()
+	root.Wait()
+
+	levels := map[string]context.Level{ // internal events keep levels of previous Log() calls
+		"cancel recursiveClosing ...": 102,
+		"recursiveClosing ...":        103,
+		"childsWaitGroup ...":         104,
+		"close ...":                   105,
+	}
+	for message, level := range levels {
+		if event := debugger.find(message); event == nil || event.Level != level {
+			t.Fatalf("unexpected event for %v: %+v", message, event)
+		}
+	}
+}
+
+func Test_LegacyObjects(t *testing.T) {
+	debugger := &objectsDebugger{}
+	root := context.NewRootContext(debugger)
+
+	root.Log("without", "level")
+	root.Log(2, "with", "level")
+
+	root.Cancel()
+	root.Wait()
+
+	debugger.ready.Lock()
+	defer debugger.ready.Unlock()
+
+	found := 0
+	for _, objects := range debugger.objects {
+		if len(objects) == 1 && objects[0] == "without,level" {
+			found++
+		}
+		if len(objects) == 2 && objects[0] == 2 && objects[1] == "with,level" {
+			found++
+		}
+	}
+	if found != 2 {
+		t.Fatalf("legacy calls are not received as objects: %v", debugger.objects)
+	}
+}
+
+func Test_ConsoleLogDebuggerUnleveledNotFiltered(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+
+	debugger := context.NewConsoleLogDebugger(int(context.LevelError), true)
+	nodePath := []context.DebugNode{{ID: 0, ComponentType: "root", ComponentName: "root"}}
+	debugger.Log(nodePath, []interface{}{"legacy message"})
+	debugger.Log(nodePath, []interface{}{int(context.LevelInfo), "filtered message"})
+
+	os.Stdout = stdout
+	writer.Close()
+
+	output, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(output), "legacy message") {
+		t.Fatalf("unleveled message is filtered: %q", output)
+	}
+	if strings.Contains(string(output), "filtered message") {
+		t.Fatalf("leveled message is not filtered: %q", output)
+	}
+}
@@ -0,0 +1,225 @@
+package context
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// FailurePolicy defines what happens when context fails (its Go panics or exits with unclosed childs)
+type FailurePolicy int
+
+const (
+	// FailurePolicyPropagate closes failed context and fails its parent with the same cause (for root context it cancels whole tree)
+	FailurePolicyPropagate FailurePolicy = 0
+	// FailurePolicyIgnore closes only failed context with all its childs
+	FailurePolicyIgnore FailurePolicy = 1
+	// FailurePolicyRestart closes failed context with all its childs and starts the same instance in new child context of the same parent after restart delay
+	FailurePolicyRestart FailurePolicy = 2
+)
+
+// default restart policy, it is used till SetRestartPolicy is not called
+const (
+	DefaultRestartMinDelay = 100 * time.Millisecond
+	DefaultRestartMaxDelay = 30 * time.Second
+)
+
+type restartPolicy struct {
+	minDelay    time.Duration
+	maxDelay    time.Duration
+	maxRestarts int // 0 is unlimited
+	restarts    int
+	ready       sync.Mutex
+}
+
+// next returns delay before the next restart or error if restarts limit is reached
+func (policy *restartPolicy) next() (time.Duration, error) {
+	policy.ready.Lock()
+	defer policy.ready.Unlock()
+
+	if policy.maxRestarts > 0 && policy.restarts >= policy.maxRestarts {
+		return 0, &TooManyRestartsError{Restarts: policy.maxRestarts}
+	}
+
+	delay := policy.minDelay
+	for i := 0; i < policy.restarts && delay < policy.maxDelay; i++ {
+		delay = delay * 2
+	}
+	if delay > policy.maxDelay {
+		delay = policy.maxDelay
+	}
+
+	policy.restarts++
+	return delay, nil
+}
+
+// PanicError ...
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", err.Value)
+}
+
+// UnclosedChildsError ...
+type UnclosedChildsError struct {
+	ComponentType string
+	ComponentName string
+}
+
+func (err *UnclosedChildsError) Error() string {
+	return fmt.Sprintf("you tries to exit from context %v[%v] that have unclosed childs. Use context.Cancel() method, instead just exiting from goroutine!", err.ComponentType, err.ComponentName)
+}
+
+// WithFailurePolicy sets failure policy of child context before it is started, so failure of instance which panics at once is handled with it too
+func WithFailurePolicy(policy FailurePolicy) ChildOption {
+	return func(options *childOptions) {
+		options.failurePolicy = policy
+	}
+}
+
+// WithRestartPolicy sets restart policy of child context before it is started (see SetRestartPolicy)
+func WithRestartPolicy(minDelay time.Duration, maxDelay time.Duration, maxRestarts int) ChildOption {
+	return func(options *childOptions) {
+		options.restartPolicy = &restartPolicy{minDelay: minDelay, maxDelay: maxDelay, maxRestarts: maxRestarts}
+	}
+}
+
+// SetFailurePolicy ...
+func (context *ctx) SetFailurePolicy(policy FailurePolicy) {
+	context.failureMutex.Lock()
+	defer context.failureMutex.Unlock()
+	context.failurePolicy = policy
+}
+
+// SetRestartPolicy ...
+func (context *ctx) SetRestartPolicy(minDelay time.Duration, maxDelay time.Duration, maxRestarts int) {
+	policy := context.getRestartPolicy()
+	policy.ready.Lock()
+	defer policy.ready.Unlock()
+	policy.minDelay = minDelay
+	policy.maxDelay = maxDelay
+	policy.maxRestarts = maxRestarts
+}
+
+func (context *ctx) getRestartPolicy() *restartPolicy {
+	context.failureMutex.Lock()
+	defer context.failureMutex.Unlock()
+	if context.restartPolicy == nil {
+		context.restartPolicy = &restartPolicy{minDelay: DefaultRestartMinDelay, maxDelay: DefaultRestartMaxDelay}
+	}
+	return context.restartPolicy
+}
+
+func (context *ctx) getFailurePolicy() FailurePolicy {
+	context.failureMutex.Lock()
+	defer context.failureMutex.Unlock()
+	return context.failurePolicy
+}
+
+// returns failure cause of current context or nil if it not failed
+func (context *ctx) getFailure() error {
+	context.failureMutex.Lock()
+	defer context.failureMutex.Unlock()
+	return context.failure
+}
+
+// only the first failure is stored, returns false if context already failed
+func (context *ctx) setFailure(cause error) bool {
+	context.failureMutex.Lock()
+	defer context.failureMutex.Unlock()
+	if context.failure != nil {
+		return false
+	}
+	context.failure = cause
+	return true
+}
+
+// runs instance loop and converts its panic to error
+func (context *ctx) run() (failure error) {
+	defer func() {
+		if r := recover(); r != nil {
+			failure = &PanicError{Value: r, Stack: debug.Stack()}
+			context.countPanic()
+			context.notify(LifecyclePanic, failure)
+		}
+	}()
+	context.instance.Go(context)
+	return nil
+}
+
+// checks that instance loop exited only after all childs were closed
+func (context *ctx) checkUnclosedChilds() error {
+	context.tree.changesAllowed.Lock()
+	defer context.tree.changesAllowed.Unlock()
+
+	if len(context.childs) != 0 {
+		node := context.debuggerNode()
+		return &UnclosedChildsError{ComponentType: node.ComponentType, ComponentName: node.ComponentName}
+	}
+	return nil
+}
+
+func (context *ctx) debuggerNode() DebugNode {
+	context.debuggerMutex.Lock()
+	defer context.debuggerMutex.Unlock()
+	return context.debuggerNodePath[len(context.debuggerNodePath)-1]
+}
+
+func (context *ctx) fail(cause error) {
+	if !context.setFailure(cause) {
+		return
+	}
+
+	context.LogEvent(LevelError, "failed", NewField("cause", cause.Error()))
+	context.CancelWithCause(cause)
+
+	if context.parent != nil && context.getFailurePolicy() == FailurePolicyPropagate {
+		context.parent.fail(cause)
+	}
+}
+
+func (context *ctx) detachFromParent() {
+	context.tree.changesAllowed.Lock()
+	defer context.tree.changesAllowed.Unlock()
+	delete(context.parent.childs, context.id)
+}
+
+// restart is delayed with clock timer, so failed context goroutine exits and does not hold parent closing
+func (context *ctx) restart() {
+	policy := context.getRestartPolicy()
+
+	delay, err := policy.next()
+	if err != nil {
+		context.LogEvent(LevelError, "restarts limit reached", NewField("error", err.Error()))
+		context.parent.fail(err)
+		return
+	}
+
+	context.LogEvent(LevelInfo, "restart scheduled", NewField("delay", delay.String()))
+
+	context.tree.clock.AfterFunc(delay, func() {
+		node := context.debuggerNode()
+
+		options := childOptions{
+			deadline:      context.deadline,
+			timeout:       context.timeout,
+			failurePolicy: FailurePolicyRestart,
+			restartPolicy: policy,
+		}
+		if context.timeout > 0 { // deadline is counted from restart, not from the first start
+			options.deadline = context.tree.clock.Now().Add(context.timeout)
+		}
+
+		restarted, err := context.parent.newChildContextFor(context.instance, node.ComponentName, node.ComponentType, options)
+		if err != nil {
+			context.LogEvent(LevelDebug, "restart skipped", NewField("error", err.Error())) // parent is closing
+			return
+		}
+
+		restarted.LogEvent(LevelInfo, "restarted")
+	})
+}
@@ -0,0 +1,294 @@
+package context_test
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+type panicNode struct {
+	started chan context.Context
+	trigger chan struct{}
+}
+
+func newPanicNode() *panicNode {
+	return &panicNode{
+		started: make(chan context.Context, 16),
+		trigger: make(chan struct{}),
+	}
+}
+
+func (node *panicNode) Go(current context.Context) {
+	node.started <- current
+loop:
+	for {
+		select {
+		case <-node.trigger:
+			panic("node crashed")
+		case _, opened := <-current.Opened():
+			if !opened {
+				break loop
+			}
+		}
+	}
+}
+
+// immediatePanicNode panics as soon as it is started, so its failure policy has to be set before start
+type immediatePanicNode struct {
+	started chan struct{}
+	panics  int32 // number of starts which panic, after them node works till closing
+}
+
+func (node *immediatePanicNode) Go(current context.Context) {
+	node.started <- struct{}{}
+	if atomic.AddInt32(&node.panics, -1) >= 0 {
+		panic("node crashed at once")
+	}
+	<-current.Opened()
+}
+
+type unclosedChildsNode struct{}
+
+func (node *unclosedChildsNode) Go(current context.Context) {
+	current.NewContextFor(newNode(), "child", "node")
+}
+
+func waitStarted(t *testing.T, node *panicNode) context.Context {
+	select {
+	case current := <-node.started:
+		return current
+	case <-time.After(5 * time.Second):
+		t.Fatal("node is not started")
+	}
+	return nil
+}
+
+func Test_PanicPropagatedToRoot(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	node := newPanicNode()
+	if _, err := root.NewContextFor(node, "0", "node"); err != nil {
+		t.Fatal(err)
+	}
+	waitStarted(t, node)
+
+	node.trigger <- struct{}{}
+
+	var panicError *context.PanicError
+	if err := root.Wait(); !errors.As(err, &panicError) {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_PanicIgnored(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	node := newPanicNode()
+	ctx, err := root.NewContextFor(node, "0", "node", context.WithFailurePolicy(context.FailurePolicyIgnore))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitStarted(t, node)
+
+	node.trigger <- struct{}{}
+
+	select {
+	case <-ctx.Opened():
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed context is not closed")
+	}
+
+	var panicError *context.PanicError
+	if !errors.As(ctx.Cause(), &panicError) {
+		t.Fatalf("failed context has unexpected cause: %v", ctx.Cause())
+	}
+
+	if _, err := root.NewContextFor(newNode(), "1", "node"); err != nil {
+		t.Fatalf("root is closed after ignored failure: %v", err)
+	}
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_ImmediatePanicIgnored(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	for i := 0; i < 100; i++ {
+		node := &immediatePanicNode{started: make(chan struct{}, 1), panics: 1}
+		ctx, err := root.NewContextFor(node, fmt.Sprintf("%v", i), "node", context.WithFailurePolicy(context.FailurePolicyIgnore))
+		if err != nil {
+			t.Fatalf("root is closed after ignored failure: %v", err)
+		}
+		<-ctx.Opened()
+	}
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_ImmediatePanicRestarted(t *testing.T) {
+	clock := context.NewFakeClock(time.Unix(0, 0))
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+
+	node := &immediatePanicNode{started: make(chan struct{}, 16), panics: 3}
+	_, err := root.NewContextFor(node, "0", "node", context.WithFailurePolicy(context.FailurePolicyRestart), context.WithRestartPolicy(time.Second, time.Second, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		<-node.started
+		waitTimers(t, clock, 1)
+		clock.Advance(time.Second)
+	}
+	<-node.started
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_PanicRestarted(t *testing.T) {
+	clock := context.NewFakeClock(time.Unix(0, 0))
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+
+	node := newPanicNode()
+	_, err := root.NewContextFor(node, "0", "node", context.WithFailurePolicy(context.FailurePolicyRestart))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		current := waitStarted(t, node)
+		if current.Err() != nil {
+			t.Fatalf("restarted context is already canceled: %v", current.Err())
+		}
+		node.trigger <- struct{}{}
+		waitTimers(t, clock, 1)
+		clock.Advance(context.DefaultRestartMaxDelay)
+	}
+	waitStarted(t, node)
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func notStarted(t *testing.T, node *panicNode) {
+	select {
+	case <-node.started:
+		t.Fatal("node is restarted before restart delay")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func Test_RestartBackoff(t *testing.T) {
+	clock := context.NewFakeClock(time.Unix(0, 0))
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+
+	node := newPanicNode()
+	_, err := root.NewContextFor(node, "0", "node", context.WithFailurePolicy(context.FailurePolicyRestart), context.WithRestartPolicy(time.Second, 3*time.Second, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		waitStarted(t, node)
+		node.trigger <- struct{}{}
+
+		waitTimers(t, clock, 1)
+		clock.Advance(delay - time.Millisecond)
+		notStarted(t, node)
+		clock.Advance(time.Millisecond)
+	}
+	waitStarted(t, node)
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_RestartsLimit(t *testing.T) {
+	clock := context.NewFakeClock(time.Unix(0, 0))
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+
+	node := newPanicNode()
+	_, err := root.NewContextFor(node, "0", "node", context.WithFailurePolicy(context.FailurePolicyRestart), context.WithRestartPolicy(time.Second, time.Second, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		waitStarted(t, node)
+		node.trigger <- struct{}{}
+		waitTimers(t, clock, 1)
+		clock.Advance(time.Second)
+	}
+	waitStarted(t, node)
+	node.trigger <- struct{}{}
+
+	var tooManyRestartsError *context.TooManyRestartsError
+	if err := root.Wait(); !errors.As(err, &tooManyRestartsError) {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_RestartedDeadlineCountedFromTimeout(t *testing.T) {
+	clock := context.NewFakeClock(time.Unix(0, 0))
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+
+	node := newPanicNode()
+	ctx, err := root.NewContextWithTimeout(time.Hour, node, "0", "node", context.WithFailurePolicy(context.FailurePolicyRestart), context.WithRestartPolicy(time.Second, time.Second, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitStarted(t, node)
+
+	clock.Advance(30 * time.Minute)
+	node.trigger <- struct{}{}
+
+	select {
+	case <-ctx.Opened():
+	case <-time.After(5 * time.Second):
+		t.Fatal("failed context is not closed")
+	}
+
+	waitTimers(t, clock, 1)
+	clock.Advance(time.Second)
+
+	restarted := waitStarted(t, node)
+	expected := time.Unix(0, 0).Add(30*time.Minute + time.Second + time.Hour)
+	if deadline, _ := restarted.Deadline(); !deadline.Equal(expected) {
+		t.Fatalf("restarted deadline %v, expected %v", deadline, expected)
+	}
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_UnclosedChilds(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	if _, err := root.NewContextFor(&unclosedChildsNode{}, "0", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	var unclosedChildsError *context.UnclosedChildsError
+	if err := root.Wait(); !errors.As(err, &unclosedChildsError) {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package context
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+type hook struct {
+	handler func(current Context)
+}
+
+// hooks are called in reverse order of registration (like defer)
+type hooks struct {
+	registered []*hook
+}
+
+func (hooks *hooks) add(handler func(current Context)) *hook {
+	hook := &hook{handler: handler}
+	hooks.registered = append(hooks.registered, hook)
+	return hook
+}
+
+func (hooks *hooks) remove(removed *hook) {
+	for i, hook := range hooks.registered {
+		if hook == removed {
+			hooks.registered = append(hooks.registered[:i:i], hooks.registered[i+1:]...)
+			return
+		}
+	}
+}
+
+func (hooks *hooks) reversed() []*hook {
+	reversed := []*hook{}
+	for i := len(hooks.registered) - 1; i >= 0; i-- {
+		reversed = append(reversed, hooks.registered[i])
+	}
+	return reversed
+}
+
+// AddOnBeforeClosing registers handler which is called before closing all child and subchild contexts, returns function which removes it
+func (context *ctx) AddOnBeforeClosing(handler func(current Context)) func() {
+	context.hooksMutex.Lock()
+	defer context.hooksMutex.Unlock()
+	hook := context.onBeforeClosing.add(handler)
+	return func() {
+		context.hooksMutex.Lock()
+		defer context.hooksMutex.Unlock()
+		context.onBeforeClosing.remove(hook)
+	}
+}
+
+// AddOnClosed registers handler which is called after current context and all its childs are closed, returns function which removes it
+func (context *ctx) AddOnClosed(handler func(current Context)) func() {
+	context.hooksMutex.Lock()
+	defer context.hooksMutex.Unlock()
+	hook := context.onClosed.add(handler)
+	return func() {
+		context.hooksMutex.Lock()
+		defer context.hooksMutex.Unlock()
+		context.onClosed.remove(hook)
+	}
+}
+
+// SetOnBeforeClosing replaces handler previously set with SetOnBeforeClosing, handlers added with AddOnBeforeClosing are kept
+func (context *ctx) SetOnBeforeClosing(handler func(current Context)) {
+	context.hooksMutex.Lock()
+	defer context.hooksMutex.Unlock()
+	if context.onBeforeClosingHook != nil {
+		context.onBeforeClosing.remove(context.onBeforeClosingHook)
+	}
+	context.onBeforeClosingHook = context.onBeforeClosing.add(handler)
+}
+
+func (context *ctx) callOnBeforeClosingHandlers() {
+	context.hooksMutex.Lock()
+	handlers := context.onBeforeClosing.reversed()
+	context.hooksMutex.Unlock()
+
+	context.callHandlers("before closing", handlers)
+}
+
+func (context *ctx) callOnClosedHandlers() {
+	context.hooksMutex.Lock()
+	handlers := context.onClosed.reversed()
+	context.hooksMutex.Unlock()
+
+	context.callHandlers("closed", handlers)
+}
+
+// handlers are called without holding hooks mutex, so they can register or remove other handlers
+func (context *ctx) callHandlers(kind string, handlers []*hook) {
+	for _, hook := range handlers {
+		context.callHandler(kind, hook.handler)
+	}
+}
+
+func (context *ctx) callHandler(kind string, handler func(current Context)) {
+	defer func() {
+		if r := recover(); r != nil {
+			context.LogEvent(LevelError, fmt.Sprintf("%v handler panicked", kind), NewField("panic", r), NewField("stack", string(debug.Stack())))
+		}
+	}()
+	handler(context)
+}
@@ -0,0 +1,80 @@
+package context_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_ClosingHooksOrder(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	ctx, err := root.NewContextFor(newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := []string{}
+	ready := sync.Mutex{}
+	record := func(name string) func(context.Context) {
+		return func(current context.Context) {
+			ready.Lock()
+			defer ready.Unlock()
+			calls = append(calls, name)
+		}
+	}
+
+	ctx.AddOnClosed(record("closed"))
+	ctx.AddOnBeforeClosing(record("first"))
+	remove := ctx.AddOnBeforeClosing(record("removed"))
+	ctx.AddOnBeforeClosing(record("second"))
+	ctx.SetOnBeforeClosing(record("replaced"))
+	ctx.SetOnBeforeClosing(record("set"))
+	remove()
+
+	root.Cancel()
+	root.Wait()
+
+	ready.Lock()
+	defer ready.Unlock()
+
+	expected := []string{"set", "second", "first", "closed"}
+	if len(calls) != len(expected) {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Fatalf("unexpected calls: %v", calls)
+		}
+	}
+}
+
+func Test_ClosingHookPanic(t *testing.T) {
+	debugger := &recordingDebugger{}
+	root := context.NewRootContext(debugger)
+
+	ctx, err := root.NewContextFor(newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := make(chan struct{}, 1)
+	ctx.AddOnBeforeClosing(func(current context.Context) {
+		called <- struct{}{}
+	})
+	ctx.AddOnBeforeClosing(func(current context.Context) {
+		panic("hook crashed")
+	})
+
+	root.Cancel()
+	root.Wait()
+
+	if len(called) != 1 {
+		t.Fatal("handler after panicked one is not called")
+	}
+
+	if event := debugger.find("before closing handler panicked"); event == nil || event.Level != context.LevelError {
+		t.Fatalf("panic is not reported: %+v", event)
+	}
+}
@@ -0,0 +1,47 @@
+package context
+
+import (
+	stdContext "context"
+	"fmt"
+	"runtime/pprof"
+	"sort"
+	"strings"
+)
+
+// GoroutinePathLabel is a pprof label with context path ("root[0]->name[id]->...") which is set for goroutines of context tree with WithGoroutineLabels option
+const GoroutinePathLabel = "context.path"
+
+// WithGoroutineLabels sets pprof labels for goroutine of each context, goroutines started from it inherit them. Besides specified labels, GoroutinePathLabel is set.
+func WithGoroutineLabels(labels map[string]string) RootOption {
+	return func(options *rootOptions) {
+		keys := []string{}
+		for key := range labels {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		options.goroutineLabels = []string{}
+		for _, key := range keys {
+			options.goroutineLabels = append(options.goroutineLabels, key, labels[key])
+		}
+	}
+}
+
+// PathString returns path in the same format as GoroutinePathLabel
+func PathString(path []DebugNode) string {
+	nodes := []string{}
+	for _, node := range path {
+		nodes = append(nodes, fmt.Sprintf("%v[%v]", node.ComponentName, node.ID))
+	}
+	return strings.Join(nodes, "->")
+}
+
+// called from context goroutine before instance loop is started
+func (context *ctx) setGoroutineLabels() {
+	if context.tree.goroutineLabels == nil {
+		return
+	}
+
+	labels := append([]string{GoroutinePathLabel, PathString(context.Path())}, context.tree.goroutineLabels...)
+	pprof.SetGoroutineLabels(pprof.WithLabels(stdContext.Background(), pprof.Labels(labels...)))
+}
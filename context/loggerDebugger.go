@@ -0,0 +1,56 @@
+package context
+
+import (
+	"fmt"
+)
+
+// EventLogger is a ring buffer logger, it is implemented by logger.Logger from github.com/mcfly722/goPackages/logger
+type EventLogger interface {
+	LogEvent(eventType int, object string, message string)
+}
+
+// event types of github.com/mcfly722/goPackages/logger, they are duplicated here to keep both modules without dependencies
+const (
+	LoggerEventTypeException = 1
+	LoggerEventTypeInfo      = 2
+	LoggerEventTypeTrace     = 3
+)
+
+// LoggerDebugger stores context tree events to EventLogger, node path is stored as event object
+type LoggerDebugger struct {
+	logger EventLogger
+}
+
+// NewLoggerDebugger ...
+func NewLoggerDebugger(logger EventLogger) Debugger {
+	return &LoggerDebugger{
+		logger: logger,
+	}
+}
+
+// LoggerEventType maps context level to logger event type
+func LoggerEventType(level Level) int {
+	switch {
+	case level <= LevelError:
+		return LoggerEventTypeException
+	case level <= LevelInfo:
+		return LoggerEventTypeInfo
+	default:
+		return LoggerEventTypeTrace
+	}
+}
+
+// Log ...
+func (loggerDebugger *LoggerDebugger) Log(nodePath []DebugNode, objects []interface{}) {
+	loggerDebugger.LogEvent(nodePath, NewEventFromObjects(objects))
+}
+
+// LogEvent ...
+func (loggerDebugger *LoggerDebugger) LogEvent(nodePath []DebugNode, event *Event) {
+	message := event.Message
+	if len(event.Fields) > 0 {
+		message = fmt.Sprintf("%v %v", message, event.FieldsString())
+	}
+
+	loggerDebugger.logger.LogEvent(LoggerEventType(event.severity()), PathString(nodePath), message)
+}
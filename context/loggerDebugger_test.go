@@ -0,0 +1,74 @@
+package context_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+// ringLogger has same LogEvent method as logger.Logger
+type ringLogger struct {
+	events []string
+	ready  sync.Mutex
+}
+
+func (logger *ringLogger) LogEvent(eventType int, object string, message string) {
+	logger.ready.Lock()
+	defer logger.ready.Unlock()
+	logger.events = append(logger.events, fmt.Sprintf("%v %v %v", eventType, object, message))
+}
+
+type crashingNode struct{}
+
+func (node *crashingNode) Go(current context.Context) {
+	current.LogEvent(context.LevelError, "crashed", context.NewField("code", 1))
+	<-current.Opened()
+}
+
+func Test_LoggerDebugger(t *testing.T) {
+	logger := &ringLogger{}
+
+	root := context.NewRootContext(context.NewLoggerDebugger(logger))
+	if _, err := root.NewContextFor(&crashingNode{}, "node", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	root.Log(2, "stopping")
+	root.Cancel()
+	root.Wait()
+
+	found := map[string]bool{}
+	for _, event := range logger.events {
+		found[event] = true
+	}
+
+	for _, expected := range []string{
+		fmt.Sprintf("%v root[0]->node[0] crashed code=1", context.LoggerEventTypeException),
+		fmt.Sprintf("%v root[0] stopping", context.LoggerEventTypeInfo),
+		fmt.Sprintf("%v root[0]->node[0] started", context.LoggerEventTypeTrace),
+	} {
+		if !found[expected] {
+			t.Fatalf("event %v is not logged", expected)
+		}
+	}
+}
+
+func Test_LoggerDebuggerLegacyLevels(t *testing.T) {
+	logger := &ringLogger{}
+	debugger := context.NewLoggerDebugger(logger)
+
+	nodePath := []context.DebugNode{{ID: 0, ComponentType: "root", ComponentName: "root"}}
+	debugger.Log(nodePath, []interface{}{0, "zero"})
+	debugger.Log(nodePath, []interface{}{2, "two"})
+
+	for i, expected := range []string{
+		fmt.Sprintf("%v root[0] zero", context.LoggerEventTypeInfo),
+		fmt.Sprintf("%v root[0] two", context.LoggerEventTypeInfo),
+	} {
+		if logger.events[i] != expected {
+			t.Fatalf("legacy level is logged as %v, expected %v", logger.events[i], expected)
+		}
+	}
+}
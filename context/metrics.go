@@ -0,0 +1,135 @@
+package context
+
+import (
+	"sync"
+	"time"
+)
+
+// ClosingDurationBuckets are upper bounds (in seconds) of closing duration histogram buckets
+var ClosingDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// Histogram ...
+type Histogram struct {
+	Buckets []float64 `json:"buckets"` // upper bounds, +Inf bucket is not included
+	Counts  []uint64  `json:"counts"`  // cumulative count of observations for each bucket
+	Sum     float64   `json:"sum"`
+	Count   uint64    `json:"count"`
+}
+
+// ComponentMetrics are counters and gauges of all contexts with the same component type
+type ComponentMetrics struct {
+	Created         uint64     `json:"created"`
+	Closed          uint64     `json:"closed"` // contexts which instance loop exited
+	Alive           int64      `json:"alive"`
+	Panics          uint64     `json:"panics"`
+	Detached        uint64     `json:"detached"` // contexts which were force-detached after grace period
+	ClosingDuration *Histogram `json:"closingDuration"`
+}
+
+// Metrics of context tree at the moment of call, key is a component type
+type Metrics struct {
+	Components map[string]*ComponentMetrics `json:"components"`
+}
+
+type treeMetrics struct {
+	components map[string]*ComponentMetrics
+	ready      sync.Mutex
+}
+
+func newTreeMetrics() *treeMetrics {
+	return &treeMetrics{components: make(map[string]*ComponentMetrics)}
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		Buckets: buckets,
+		Counts:  make([]uint64, len(buckets)),
+	}
+}
+
+func (histogram *Histogram) observe(value float64) {
+	for i, bucket := range histogram.Buckets {
+		if value <= bucket {
+			histogram.Counts[i]++
+		}
+	}
+	histogram.Sum += value
+	histogram.Count++
+}
+
+func (histogram *Histogram) copy() *Histogram {
+	counts := make([]uint64, len(histogram.Counts))
+	copy(counts, histogram.Counts)
+	return &Histogram{
+		Buckets: histogram.Buckets,
+		Counts:  counts,
+		Sum:     histogram.Sum,
+		Count:   histogram.Count,
+	}
+}
+
+// update calls function under metrics lock with component metrics created on demand
+func (metrics *treeMetrics) update(componentType string, update func(component *ComponentMetrics)) {
+	metrics.ready.Lock()
+	defer metrics.ready.Unlock()
+
+	component, found := metrics.components[componentType]
+	if !found {
+		component = &ComponentMetrics{ClosingDuration: newHistogram(ClosingDurationBuckets)}
+		metrics.components[componentType] = component
+	}
+	update(component)
+}
+
+func (metrics *treeMetrics) snapshot() *Metrics {
+	metrics.ready.Lock()
+	defer metrics.ready.Unlock()
+
+	snapshot := &Metrics{Components: make(map[string]*ComponentMetrics)}
+	for componentType, component := range metrics.components {
+		copied := *component
+		copied.ClosingDuration = component.ClosingDuration.copy()
+		snapshot.Components[componentType] = &copied
+	}
+	return snapshot
+}
+
+func (context *ctx) metrics() *Metrics {
+	return context.tree.metrics.snapshot()
+}
+
+func (context *ctx) componentType() string {
+	return context.debuggerNode().ComponentType
+}
+
+func (context *ctx) countCreated() {
+	context.tree.metrics.update(context.componentType(), func(component *ComponentMetrics) {
+		component.Created++
+		component.Alive++
+	})
+}
+
+func (context *ctx) countClosed() {
+	context.tree.metrics.update(context.componentType(), func(component *ComponentMetrics) {
+		component.Closed++
+		component.Alive--
+	})
+}
+
+func (context *ctx) countPanic() {
+	context.tree.metrics.update(context.componentType(), func(component *ComponentMetrics) {
+		component.Panics++
+	})
+}
+
+func (context *ctx) countDetached() {
+	context.tree.metrics.update(context.componentType(), func(component *ComponentMetrics) {
+		component.Detached++
+	})
+}
+
+func (context *ctx) observeClosingDuration(duration time.Duration) {
+	context.tree.metrics.update(context.componentType(), func(component *ComponentMetrics) {
+		component.ClosingDuration.observe(duration.Seconds())
+	})
+}
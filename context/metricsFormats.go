@@ -0,0 +1,86 @@
+package context
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type prometheusMetric struct {
+	name      string
+	help      string
+	kind      string
+	value     func(component *ComponentMetrics) string
+	histogram bool
+}
+
+var prometheusMetrics = []*prometheusMetric{
+	{name: "context_nodes_created_total", help: "Number of created contexts.", kind: "counter", value: func(component *ComponentMetrics) string { return formatUint(component.Created) }},
+	{name: "context_nodes_closed_total", help: "Number of contexts which instance loop exited.", kind: "counter", value: func(component *ComponentMetrics) string { return formatUint(component.Closed) }},
+	{name: "context_nodes_alive", help: "Number of contexts which instance loop is running.", kind: "gauge", value: func(component *ComponentMetrics) string { return strconv.FormatInt(component.Alive, 10) }},
+	{name: "context_panics_total", help: "Number of panics recovered in contexts.", kind: "counter", value: func(component *ComponentMetrics) string { return formatUint(component.Panics) }},
+	{name: "context_nodes_detached_total", help: "Number of contexts force-detached after grace period.", kind: "counter", value: func(component *ComponentMetrics) string { return formatUint(component.Detached) }},
+	{name: "context_closing_duration_seconds", help: "Time from closing start till instance loop exit.", kind: "histogram", histogram: true},
+}
+
+func formatUint(value uint64) string {
+	return strconv.FormatUint(value, 10)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// ToPrometheus returns metrics in Prometheus text exposition format
+func (metrics *Metrics) ToPrometheus() string {
+	componentTypes := []string{}
+	for componentType := range metrics.Components {
+		componentTypes = append(componentTypes, componentType)
+	}
+	sort.Strings(componentTypes)
+
+	builder := &strings.Builder{}
+
+	for _, metric := range prometheusMetrics {
+		builder.WriteString(fmt.Sprintf("# HELP %v %v\n", metric.name, metric.help))
+		builder.WriteString(fmt.Sprintf("# TYPE %v %v\n", metric.name, metric.kind))
+
+		for _, componentType := range componentTypes {
+			component := metrics.Components[componentType]
+			label := fmt.Sprintf(`component_type="%v"`, labelValueEscaper.Replace(componentType))
+
+			if !metric.histogram {
+				builder.WriteString(fmt.Sprintf("%v{%v} %v\n", metric.name, label, metric.value(component)))
+				continue
+			}
+
+			histogram := component.ClosingDuration
+			for i, bucket := range histogram.Buckets {
+				builder.WriteString(fmt.Sprintf("%v_bucket{%v,le=\"%v\"} %v\n", metric.name, label, formatFloat(bucket), histogram.Counts[i]))
+			}
+			builder.WriteString(fmt.Sprintf("%v_bucket{%v,le=\"+Inf\"} %v\n", metric.name, label, histogram.Count))
+			builder.WriteString(fmt.Sprintf("%v_sum{%v} %v\n", metric.name, label, formatFloat(histogram.Sum)))
+			builder.WriteString(fmt.Sprintf("%v_count{%v} %v\n", metric.name, label, histogram.Count))
+		}
+	}
+
+	return builder.String()
+}
+
+type metricsHandler struct {
+	root RootContext
+}
+
+// NewMetricsHandler returns http handler which renders context tree metrics in Prometheus text format
+func NewMetricsHandler(root RootContext) http.Handler {
+	return &metricsHandler{root: root}
+}
+
+func (handler *metricsHandler) ServeHTTP(response http.ResponseWriter, request *http.Request) {
+	response.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	response.Write([]byte(handler.root.Metrics().ToPrometheus()))
+}
@@ -0,0 +1,110 @@
+package context_test
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_Metrics(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	for _, name := range []string{"1", "2"} {
+		if _, err := root.NewContextFor(newNode(), name, "node"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	panicNode := newPanicNode()
+	panicCtx, err := root.NewContextFor(panicNode, "panic", "panicking")
+	if err != nil {
+		t.Fatal(err)
+	}
+	panicCtx.SetFailurePolicy(context.FailurePolicyIgnore)
+	<-panicNode.started
+	close(panicNode.trigger)
+	<-panicCtx.Done()
+
+	metrics := root.Metrics()
+
+	if node := metrics.Components["node"]; node == nil || node.Created != 2 || node.Alive != 2 || node.Closed != 0 {
+		t.Fatalf("unexpected node metrics: %+v", node)
+	}
+
+	if panicking := metrics.Components["panicking"]; panicking == nil || panicking.Panics != 1 || panicking.Alive != 0 {
+		t.Fatalf("unexpected panicking metrics: %+v", panicking)
+	}
+
+	root.Cancel()
+	root.Wait()
+
+	if node := root.Metrics().Components["node"]; node.Closed != 2 || node.Alive != 0 || node.ClosingDuration.Count != 2 {
+		t.Fatalf("unexpected node metrics after cancel: %+v", node)
+	}
+}
+
+func Test_MetricsHandler(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	if _, err := root.NewContextFor(newNode(), "1", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(context.NewMetricsHandler(root))
+	defer server.Close()
+
+	response, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"# TYPE context_nodes_created_total counter\n",
+		`context_nodes_created_total{component_type="node"} 1` + "\n",
+		`context_nodes_alive{component_type="root"} 1` + "\n",
+		`context_closing_duration_seconds_bucket{component_type="node",le="+Inf"} 0` + "\n",
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Fatalf("%q not found in:\n%v", expected, string(body))
+		}
+	}
+
+	root.Cancel()
+	root.Wait()
+}
+
+// advancingNode moves fake clock forward while it is closing
+type advancingNode struct {
+	clock *context.FakeClock
+}
+
+func (node *advancingNode) Go(current context.Context) {
+	<-current.Opened()
+	node.clock.Advance(3 * time.Second)
+}
+
+func Test_MetricsClosingDurationByClock(t *testing.T) {
+	clock := context.NewFakeClock(time.Unix(0, 0))
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+
+	if _, err := root.NewContextFor(&advancingNode{clock: clock}, "1", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	root.Cancel()
+	root.Wait()
+
+	if duration := root.Metrics().Components["node"].ClosingDuration; duration.Count != 1 || duration.Sum != 3 {
+		t.Fatalf("closing duration is not measured by context tree clock: %+v", duration)
+	}
+}
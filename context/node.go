@@ -0,0 +1,57 @@
+package context
+
+import (
+	"sort"
+)
+
+// ID returns context identifier which is unique among its siblings
+func (context *ctx) ID() int64 {
+	return context.id
+}
+
+// Path returns copy of nodes path from root to current context
+func (context *ctx) Path() []DebugNode {
+	context.debuggerMutex.Lock()
+	defer context.debuggerMutex.Unlock()
+	path := make([]DebugNode, len(context.debuggerNodePath))
+	copy(path, context.debuggerNodePath)
+	return path
+}
+
+// Parent returns nil for root context
+func (context *ctx) Parent() Context {
+	if context.parent == nil {
+		return nil
+	}
+	return context.parent
+}
+
+// Children returns not detached yet child contexts sorted by ID
+func (context *ctx) Children() []Context {
+	context.tree.changesAllowed.Lock()
+	childs := []*ctx{}
+	for _, child := range context.childs {
+		childs = append(childs, child)
+	}
+	context.tree.changesAllowed.Unlock()
+
+	sort.Slice(childs, func(i int, j int) bool {
+		return childs[i].id < childs[j].id
+	})
+
+	children := []Context{}
+	for _, child := range childs {
+		children = append(children, child)
+	}
+	return children
+}
+
+// State ...
+func (context *ctx) State() NodeState {
+	return context.state()
+}
+
+// Done returns channel which closes when instance Go() returns
+func (context *ctx) Done() chan struct{} {
+	return context.done
+}
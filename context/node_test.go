@@ -0,0 +1,68 @@
+package context_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_NodeAccessors(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	parent, err := root.NewContextFor(newNode(), "parent", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := parent.NewContextFor(newNode(), "first", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := parent.NewContextFor(newNode(), "second", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parent.Parent() == nil || parent.Parent().Parent() != nil {
+		t.Fatal("unexpected parents chain")
+	}
+
+	path := second.Path()
+	if len(path) != 3 || path[0].ComponentName != "root" || path[1].ComponentName != "parent" || path[2].ComponentName != "second" || path[2].ID != second.ID() {
+		t.Fatalf("unexpected path: %+v", path)
+	}
+
+	children := parent.Children()
+	if len(children) != 2 || children[0] != first || children[1] != second {
+		t.Fatalf("unexpected children: %v", children)
+	}
+
+	if first.State() != context.NodeStateRunning {
+		t.Fatalf("unexpected state: %v", first.State())
+	}
+
+	first.Cancel() // only first child is closed, parent is still running
+
+	select {
+	case <-first.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("child loop is not finished after cancel")
+	}
+
+	if first.State() != context.NodeStateClosed || parent.State() != context.NodeStateRunning || second.State() != context.NodeStateRunning {
+		t.Fatalf("unexpected states: %v %v %v", first.State(), parent.State(), second.State())
+	}
+
+	for i := 0; i < 100 && len(parent.Children()) != 1; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if children := parent.Children(); len(children) != 1 || children[0] != second {
+		t.Fatalf("closed child is not detached: %v", children)
+	}
+
+	root.Cancel()
+	root.Wait()
+}
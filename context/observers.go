@@ -0,0 +1,173 @@
+package context
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// LifecycleEventType ...
+type LifecycleEventType string
+
+const (
+	// LifecycleNodeCreated context is attached to its parent
+	LifecycleNodeCreated LifecycleEventType = "created"
+	// LifecycleGoStarted instance Go() is going to be called (it is not sent for passive contexts)
+	LifecycleGoStarted LifecycleEventType = "started"
+	// LifecycleCancelRequested context is canceled by Cancel(), CancelWithCause(), deadline or failure (it is sent only for canceled context, not for its childs)
+	LifecycleCancelRequested LifecycleEventType = "cancelRequested"
+	// LifecycleBeforeClosing before closing handlers are called, childs are not closed yet
+	LifecycleBeforeClosing LifecycleEventType = "beforeClosing"
+	// LifecycleGoFinished instance Go() returned (it is not sent for passive contexts)
+	LifecycleGoFinished LifecycleEventType = "finished"
+	// LifecycleNodeClosed context is closed with all its childs, closing hooks are called and context is detached from parent
+	LifecycleNodeClosed LifecycleEventType = "closed"
+	// LifecyclePanic instance Go() panicked
+	LifecyclePanic LifecycleEventType = "panic"
+)
+
+// LifecycleEvent ...
+type LifecycleEvent struct {
+	Type    LifecycleEventType
+	Time    time.Time
+	Path    []DebugNode // it is shared between observers, do not change it
+	Cause   error       // cancellation cause for cancelRequested and closed events, PanicError for panic event
+	Dropped uint64      // number of events which were dropped for this observer before current one because its queue was full
+}
+
+// Observer receives lifecycle events of context tree nodes in its own goroutine
+type Observer interface {
+	OnEvent(event *LifecycleEvent)
+}
+
+// ObserverFunc ...
+type ObserverFunc func(event *LifecycleEvent)
+
+// OnEvent ...
+func (observer ObserverFunc) OnEvent(event *LifecycleEvent) {
+	observer(event)
+}
+
+type subscription struct {
+	observer Observer
+	queue    chan *LifecycleEvent
+	dropped  uint64
+}
+
+type observers struct {
+	subscriptions []*subscription
+	closed        bool // root context is closed, no events would be sent anymore
+	ready         sync.Mutex
+}
+
+// DefaultObserverQueueSize is used when observer is added with queue size which is not positive
+const DefaultObserverQueueSize = 1024
+
+type rootObserver struct {
+	observer  Observer
+	queueSize int
+}
+
+// WithObserver adds observer before root context is created, so it receives events of root context too (AddObserver could miss them)
+func WithObserver(observer Observer, queueSize int) RootOption {
+	return func(options *rootOptions) {
+		options.observers = append(options.observers, &rootObserver{observer: observer, queueSize: queueSize})
+	}
+}
+
+// addObserver starts observer goroutine, events are dropped when its queue is full, so slow observer does not block context tree
+func (context *ctx) addObserver(observer Observer, queueSize int) func() {
+	observers := &context.tree.observers
+
+	if queueSize <= 0 {
+		queueSize = DefaultObserverQueueSize
+	}
+
+	observers.ready.Lock()
+	defer observers.ready.Unlock()
+
+	if observers.closed {
+		return func() {}
+	}
+
+	subscription := &subscription{
+		observer: observer,
+		queue:    make(chan *LifecycleEvent, queueSize),
+	}
+	observers.subscriptions = append(observers.subscriptions, subscription)
+
+	go context.deliver(subscription)
+
+	return func() {
+		observers.ready.Lock()
+		defer observers.ready.Unlock()
+		for i, current := range observers.subscriptions {
+			if current == subscription {
+				observers.subscriptions = append(observers.subscriptions[:i:i], observers.subscriptions[i+1:]...)
+				context.closeSubscription(subscription)
+				return
+			}
+		}
+	}
+}
+
+func (context *ctx) deliver(subscription *subscription) {
+	for event := range subscription.queue {
+		context.deliverEvent(subscription.observer, event)
+	}
+}
+
+// observer panic is reported, but next events are still delivered
+func (context *ctx) deliverEvent(observer Observer, event *LifecycleEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			context.LogEvent(LevelError, "observer panicked", NewField("panic", r), NewField("stack", string(debug.Stack())))
+		}
+	}()
+	observer.OnEvent(event)
+}
+
+func (context *ctx) notify(eventType LifecycleEventType, cause error) {
+	observers := &context.tree.observers
+
+	observers.ready.Lock()
+	defer observers.ready.Unlock()
+
+	if observers.closed || len(observers.subscriptions) == 0 {
+		return
+	}
+
+	path := context.Path()
+	now := time.Now()
+
+	for _, subscription := range observers.subscriptions {
+		select {
+		case subscription.queue <- &LifecycleEvent{Type: eventType, Time: now, Path: path, Cause: cause, Dropped: subscription.dropped}:
+			subscription.dropped = 0
+		default:
+			subscription.dropped++
+		}
+	}
+}
+
+// called when root context is closed (after its closed event), observers goroutines exit after delivering queued events
+func (context *ctx) closeObservers() {
+	observers := &context.tree.observers
+
+	observers.ready.Lock()
+	defer observers.ready.Unlock()
+
+	observers.closed = true
+	for _, subscription := range observers.subscriptions {
+		context.closeSubscription(subscription)
+	}
+	observers.subscriptions = nil
+}
+
+// events dropped after the last queued one could not be reported with Dropped field, so they are logged
+func (context *ctx) closeSubscription(subscription *subscription) {
+	if subscription.dropped > 0 {
+		context.LogEvent(LevelWarning, "observer events dropped", NewField("dropped", subscription.dropped))
+	}
+	close(subscription.queue)
+}
@@ -0,0 +1,204 @@
+package context_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+type recordingObserver struct {
+	events []*context.LifecycleEvent
+	closed chan struct{}
+	ready  sync.Mutex
+}
+
+func newRecordingObserver() *recordingObserver {
+	return &recordingObserver{closed: make(chan struct{})}
+}
+
+func (observer *recordingObserver) OnEvent(event *context.LifecycleEvent) {
+	observer.ready.Lock()
+	defer observer.ready.Unlock()
+	observer.events = append(observer.events, event)
+	if event.Type == context.LifecycleNodeClosed && len(event.Path) == 1 {
+		close(observer.closed)
+	}
+}
+
+// types of events for node with specified name
+func (observer *recordingObserver) types(componentName string) []context.LifecycleEventType {
+	observer.ready.Lock()
+	defer observer.ready.Unlock()
+	types := []context.LifecycleEventType{}
+	for _, event := range observer.events {
+		if event.Path[len(event.Path)-1].ComponentName == componentName {
+			types = append(types, event.Type)
+		}
+	}
+	return types
+}
+
+func Test_Observer(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	observer := newRecordingObserver()
+	root.AddObserver(observer, 100)
+
+	if _, err := root.NewContextFor(newNode(), "node", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	panicNode := newPanicNode()
+	panicCtx, err := root.NewContextFor(panicNode, "panic", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	panicCtx.SetFailurePolicy(context.FailurePolicyIgnore)
+	<-panicNode.started
+	close(panicNode.trigger)
+	<-panicCtx.Done()
+	for len(root.Children()) > 1 { // failed node is closed and detached
+		time.Sleep(time.Millisecond)
+	}
+
+	root.Cancel()
+	root.Wait()
+
+	select {
+	case <-observer.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("root closed event is not received")
+	}
+
+	expected := map[string][]context.LifecycleEventType{
+		"node":  {context.LifecycleNodeCreated, context.LifecycleGoStarted, context.LifecycleBeforeClosing, context.LifecycleGoFinished, context.LifecycleNodeClosed},
+		"panic": {context.LifecycleNodeCreated, context.LifecycleGoStarted, context.LifecyclePanic, context.LifecycleGoFinished, context.LifecycleCancelRequested, context.LifecycleBeforeClosing, context.LifecycleNodeClosed},
+	}
+
+	if types := observer.types("root"); len(types) < 3 || types[len(types)-1] != context.LifecycleNodeClosed { // root could be started before observer is added
+		t.Fatalf("unexpected root events: %v", types)
+	}
+
+	for name, expectedTypes := range expected {
+		types := observer.types(name)
+		if len(types) != len(expectedTypes) {
+			t.Fatalf("unexpected %v events: %v", name, types)
+		}
+		for i := range types {
+			if types[i] != expectedTypes[i] {
+				t.Fatalf("unexpected %v events: %v", name, types)
+			}
+		}
+	}
+}
+
+func Test_SlowObserver(t *testing.T) {
+	debugger := &recordingDebugger{}
+	root := context.NewRootContext(debugger)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	root.AddObserver(context.ObserverFunc(func(event *context.LifecycleEvent) {
+		<-release
+	}), 1)
+
+	for _, name := range []string{"1", "2", "3"} {
+		if _, err := root.NewContextFor(newNode(), name, "node"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root.Cancel()
+
+	select { // slow observer does not block closing
+	case <-root.Std().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("tree closing is blocked by observer")
+	}
+	root.Wait()
+
+	if event := debugger.find("observer events dropped"); event == nil || event.Fields[0].Value.(uint64) == 0 {
+		t.Fatalf("dropped events are not reported: %+v", event)
+	}
+}
+
+func Test_ObserverDefaultQueueSize(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	observer := newRecordingObserver()
+	root.AddObserver(observer, -1) // negative queue size does not panic
+
+	if _, err := root.NewContextFor(newNode(), "node", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	root.Cancel()
+	root.Wait()
+
+	select {
+	case <-observer.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("root closed event is not received")
+	}
+
+	if types := observer.types("node"); len(types) != 5 {
+		t.Fatalf("unexpected node events: %v", types)
+	}
+}
+
+func Test_ObserverOption(t *testing.T) {
+	observer := newRecordingObserver()
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithObserver(observer, 100))
+
+	root.Cancel()
+	root.Wait()
+
+	select {
+	case <-observer.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("root closed event is not received")
+	}
+
+	types := observer.types("root")
+	if len(types) != 6 || types[0] != context.LifecycleNodeCreated || types[len(types)-1] != context.LifecycleNodeClosed { // started could be received after cancelRequested
+		t.Fatalf("unexpected root events: %v", types)
+	}
+}
+
+func Test_CancelRequestedOnce(t *testing.T) {
+	observer := newRecordingObserver()
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithObserver(observer, 100))
+
+	ctx, err := root.NewContextFor(newNode(), "node", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx.CancelWithCause(errors.New("first"))
+	ctx.CancelWithCause(errors.New("second"))
+	ctx.Cancel()
+	<-ctx.Done()
+
+	root.Cancel()
+	root.Wait()
+
+	select {
+	case <-observer.closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("root closed event is not received")
+	}
+
+	requested := 0
+	for _, eventType := range observer.types("node") {
+		if eventType == context.LifecycleCancelRequested {
+			requested++
+		}
+	}
+	if requested != 1 {
+		t.Fatalf("cancelRequested is sent %v times: %v", requested, observer.types("node"))
+	}
+}
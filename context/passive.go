@@ -0,0 +1,37 @@
+package context
+
+// passiveInstance marks context which has no loop and goroutine
+type passiveInstance struct{}
+
+// Go is never called for passive context
+func (instance *passiveInstance) Go(current Context) {}
+
+// NewPassiveContext creates child context without goroutine. It is closed in the same order with others, calls closing hooks, could have its own childs, but it has no instance loop, so it is closed as soon as all its childs are closed
+func (context *ctx) NewPassiveContext(componentName string, componentType string) (Context, error) {
+	return context.newChildContextFor(&passiveInstance{}, componentName, componentType, childOptions{})
+}
+
+func (context *ctx) isPassive() bool {
+	_, passive := context.instance.(*passiveInstance)
+	return passive
+}
+
+// passive context is started without goroutine and loopWaitGroup, so closing does not wait for it
+func (context *ctx) startPassive() {
+	context.startSpan()
+	context.LogEvent(LevelDebug, "started passive")
+}
+
+// does the same what goroutine of active context does after loop exit
+func (context *ctx) finishPassive() {
+	context.setFinished()
+	context.countClosed()
+	context.LogEvent(LevelDebug, "finished passive")
+
+	if context.parent != nil {
+		context.detachFromParent()
+	}
+
+	context.endSpan()
+	context.releaseParent()
+}
@@ -0,0 +1,67 @@
+package context_test
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_PassiveContexts(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(50, true))
+
+	server, err := root.NewContextFor(newNode(), "server", "server")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goroutines := runtime.NumGoroutine()
+
+	closed := int64(0)
+	connections := []context.Context{}
+
+	for i := 0; i < 10000; i++ {
+		connection, err := server.NewPassiveContext(fmt.Sprintf("%v", i), "connection")
+		if err != nil {
+			t.Fatal(err)
+		}
+		connection.AddOnClosed(func(current context.Context) {
+			atomic.AddInt64(&closed, 1)
+		})
+		connections = append(connections, connection)
+	}
+
+	if runtime.NumGoroutine() > goroutines+10 {
+		t.Fatalf("passive contexts started goroutines: %v -> %v", goroutines, runtime.NumGoroutine())
+	}
+
+	connections[0].Cancel() // only one connection is closed
+
+	select {
+	case <-connections[0].Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("passive context is not closed")
+	}
+
+	for i := 0; i < 100 && len(server.Children()) != 9999; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(server.Children()) != 9999 || server.State() != context.NodeStateRunning {
+		t.Fatalf("unexpected server state: %v childs, %v", len(server.Children()), server.State())
+	}
+
+	root.Cancel()
+	root.Wait()
+
+	if atomic.LoadInt64(&closed) != 10000 {
+		t.Fatalf("closed hooks called %v times", closed)
+	}
+
+	if connection := root.Metrics().Components["connection"]; connection.Created != 10000 || connection.Alive != 0 {
+		t.Fatalf("unexpected connection metrics: %+v", connection)
+	}
+}
@@ -1,11 +1,50 @@
 package context
 
+import (
+	stdContext "context"
+	"os"
+	"time"
+)
+
 // RootContext ...
 type RootContext interface {
-	NewContextFor(instance ContextedInstance, componentName string, componentType string) (Context, error) // create new child context
-	Cancel()                                                                                               // cancel root context with all childs
-	Wait()                                                                                                 // waits till root context would be closed
-	Log(vars ...interface{})                                                                               // log context event
+	NewContextFor(instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error)                                // create new child context, options are applied before it is started
+	NewContextForStd(std stdContext.Context, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error)     // create new child context which would be canceled when std context is done
+	NewContextWithDeadline(deadline time.Time, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error)   // create new child context which would be canceled when deadline passes
+	NewPassiveContext(componentName string, componentType string) (Context, error)                                                                                // create new child context without goroutine
+	NewContextWithTimeout(timeout time.Duration, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) // create new child context which would be canceled after timeout
+	Cancel()                                                                                                                                                      // cancel root context with all childs
+	CancelWithCause(cause error)                                                                                                                                  // cancel root context with all childs, cause would be returned by Wait()
+	Wait() error                                                                                                                                                  // waits till root context would be closed, returns cause passed to CancelWithCause() or nil
+	LogEvent(level Level, message string, fields ...Field)                                                                                                        // log context event with level, message and key/value fields
+	Log(vars ...interface{})                                                                                                                                      // log context event
+	Std() stdContext.Context                                                                                                                                      // standard library context what is done when root context closes
+	Children() []Context                                                                                                                                          // root childs which are not closed and detached yet, sorted by ID
+	Clock() Clock                                                                                                                                                 // clock of context tree, it is real clock unless WithClock option is used
+	AddObserver(observer Observer, queueSize int) func()                                                                                                          // observer receives lifecycle events of all contexts through queue with queueSize capacity (events are dropped when it is full, DefaultObserverQueueSize is used if it is not positive), returns function which removes observer
+	Metrics() *Metrics                                                                                                                                            // returns counters and gauges of the whole context tree per component type
+	Snapshot() *NodeSnapshot                                                                                                                                      // returns current state of the whole context tree
+	WithValue(key interface{}, value interface{}) RootContext                                                                                                     // stores value in root context, it is available for all contexts of the tree
+	Value(key interface{}) interface{}                                                                                                                            // returns value stored in root context
+	SetGracePeriod(gracePeriod time.Duration)                                                                                                                     // grace period for root and all childs created after this call
+	Shutdown(timeout time.Duration) *ShutdownReport                                                                                                               // cancels root context and waits till it closes, but not longer than timeout
+	SetLoggedValues(keys ...interface{})                                                                                                                          // selected values are added as fields to every logged event of contexts where they are available
+}
+
+// RootOption ...
+type RootOption func(options *rootOptions)
+
+// options which are applied before root context is started
+type rootOptions struct {
+	shutdownSignals   []os.Signal
+	reload            func()
+	signalSource      <-chan os.Signal // nil if OS signals are handled
+	exit              func(code int)   // nil if os.Exit is called
+	tracer            Tracer
+	goroutineLabels   []string // key and value pairs
+	clock             Clock
+	observers         []*rootObserver
+	concurrentClosing bool
 }
 
 // Root ...
@@ -27,10 +66,17 @@ loop:
 }
 
 // NewRootContext ...
-func NewRootContext(debugger Debugger) RootContext {
+func NewRootContext(debugger Debugger, options ...RootOption) RootContext {
 	root := &Root{}
 
-	root.ctx, _ = newContextFor(root, debugger)
+	rootOptions := &rootOptions{clock: NewRealClock()}
+	for _, option := range options {
+		option(rootOptions)
+	}
+
+	root.ctx, _ = newContextFor(root, debugger, rootOptions)
+
+	root.handleSignals(rootOptions)
 
 	return root
 }
@@ -40,23 +86,129 @@ func (root *Root) Cancel() {
 	root.ctx.Cancel()
 }
 
+// CancelWithCause ...
+func (root *Root) CancelWithCause(cause error) {
+	root.ctx.CancelWithCause(cause)
+}
+
 // Wait ...
-func (root *Root) Wait() {
+func (root *Root) Wait() error {
 	root.ctx.wait()
+	if _, ok := root.ctx.Cause().(*CanceledError); ok {
+		return nil
+	}
+	return root.ctx.Cause()
 }
 
 // Log ...
 func (root *Root) Log(arguments ...interface{}) {
-	objects := make([]interface{}, 0)
+	root.ctx.log(NewEventFromObjects(arguments))
+}
+
+// LogEvent ...
+func (root *Root) LogEvent(level Level, message string, fields ...Field) {
+	root.ctx.LogEvent(level, message, fields...)
+}
 
-	for _, argument := range arguments {
-		objects = append(objects, argument)
+// NewContextFor ...
+func (root *Root) NewContextFor(instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) {
+	return root.ctx.NewContextFor(instance, componentName, componentType, options...)
+}
+
+// NewContextForStd ...
+func (root *Root) NewContextForStd(std stdContext.Context, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) {
+	return root.ctx.NewContextForStd(std, instance, componentName, componentType, options...)
+}
+
+// NewContextWithDeadline ...
+func (root *Root) NewContextWithDeadline(deadline time.Time, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) {
+	return root.ctx.NewContextWithDeadline(deadline, instance, componentName, componentType, options...)
+}
+
+// NewContextWithTimeout ...
+func (root *Root) NewContextWithTimeout(timeout time.Duration, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) {
+	return root.ctx.NewContextWithTimeout(timeout, instance, componentName, componentType, options...)
+}
+
+// Std ...
+func (root *Root) Std() stdContext.Context {
+	return root.ctx.Std()
+}
+
+// Snapshot ...
+func (root *Root) Snapshot() *NodeSnapshot {
+	return root.ctx.snapshot()
+}
+
+// WithValue ...
+func (root *Root) WithValue(key interface{}, value interface{}) RootContext {
+	root.ctx.WithValue(key, value)
+	return root
+}
+
+// Value ...
+func (root *Root) Value(key interface{}) interface{} {
+	return root.ctx.Value(key)
+}
+
+// SetLoggedValues ...
+func (root *Root) SetLoggedValues(keys ...interface{}) {
+	root.ctx.setLoggedValues(keys)
+}
+
+// SetGracePeriod ...
+func (root *Root) SetGracePeriod(gracePeriod time.Duration) {
+	root.ctx.SetGracePeriod(gracePeriod)
+}
+
+// Shutdown returns report with contexts which failed to close in time
+func (root *Root) Shutdown(timeout time.Duration) *ShutdownReport {
+	clock := root.ctx.Clock()
+	startedAt := clock.Now()
+
+	root.ctx.Cancel()
+
+	closed := make(chan struct{})
+	go func() {
+		root.ctx.wait()
+		close(closed)
+	}()
+
+	expired := make(chan struct{})
+	timer := clock.AfterFunc(timeout, func() { close(expired) })
+	defer timer.Stop()
+
+	completed := true
+	select {
+	case <-closed:
+	case <-expired:
+		completed = false
 	}
 
-	root.ctx.log(objects)
+	return root.ctx.shutdownReport(completed, clock.Now().Sub(startedAt))
 }
 
-// NewContextFor ...
-func (root *Root) NewContextFor(instance ContextedInstance, componentName string, componentType string) (Context, error) {
-	return root.ctx.NewContextFor(instance, componentName, componentType)
+// Children ...
+func (root *Root) Children() []Context {
+	return root.ctx.Children()
+}
+
+// Clock ...
+func (root *Root) Clock() Clock {
+	return root.ctx.Clock()
+}
+
+// Metrics ...
+func (root *Root) Metrics() *Metrics {
+	return root.ctx.metrics()
+}
+
+// NewPassiveContext ...
+func (root *Root) NewPassiveContext(componentName string, componentType string) (Context, error) {
+	return root.ctx.NewPassiveContext(componentName, componentType)
+}
+
+// AddObserver ...
+func (root *Root) AddObserver(observer Observer, queueSize int) func() {
+	return root.ctx.addObserver(observer, queueSize)
 }
@@ -0,0 +1,236 @@
+package context
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StuckNode is a context which loop did not exit in time after its Opened() channel was closed
+type StuckNode struct {
+	ID            int64         `json:"id"`
+	ComponentType string        `json:"componentType"`
+	ComponentName string        `json:"componentName"`
+	Path          string        `json:"path"`
+	Detached      bool          `json:"detached"` // context was force-detached from its parent after grace period
+	GracePeriod   time.Duration `json:"gracePeriodNs,omitempty"`
+}
+
+// ShutdownReport ...
+type ShutdownReport struct {
+	Completed bool          `json:"completed"` // false if root context was not closed within shutdown timeout
+	Duration  time.Duration `json:"durationNs"`
+	Stuck     []*StuckNode  `json:"stuck"`
+}
+
+// SetGracePeriod limits time which context loop has to exit after its Opened() channel is closed, zero (default) is unlimited
+func (context *ctx) SetGracePeriod(gracePeriod time.Duration) {
+	context.shutdownMutex.Lock()
+	defer context.shutdownMutex.Unlock()
+	context.gracePeriod = gracePeriod
+}
+
+func (context *ctx) getGracePeriod() time.Duration {
+	context.shutdownMutex.Lock()
+	defer context.shutdownMutex.Unlock()
+	return context.gracePeriod
+}
+
+// SetClosingOrder defines order of closing among siblings, lower orders are closed first, siblings with the same order are closed one by one (or simultaneously with WithConcurrentClosing option)
+func (context *ctx) SetClosingOrder(order int) {
+	context.shutdownMutex.Lock()
+	defer context.shutdownMutex.Unlock()
+	context.closingOrder = order
+}
+
+func (context *ctx) getClosingOrder() int {
+	context.shutdownMutex.Lock()
+	defer context.shutdownMutex.Unlock()
+	return context.closingOrder
+}
+
+// WithConcurrentClosing closes siblings with the same closing order simultaneously, each active one in its own goroutine, so slow sibling does not delay closing of others
+func WithConcurrentClosing() RootOption {
+	return func(options *rootOptions) {
+		options.concurrentClosing = true
+	}
+}
+
+// groups childs by closing order, groups are sorted from the lowest order, childs inside group are sorted in order of creation
+func closingGroups(childs []*ctx) [][]*ctx {
+	sort.Slice(childs, func(i int, j int) bool {
+		return childs[i].id < childs[j].id
+	})
+
+	orders := make(map[int][]*ctx)
+	for _, child := range childs {
+		order := child.getClosingOrder()
+		orders[order] = append(orders[order], child)
+	}
+
+	keys := []int{}
+	for order := range orders {
+		keys = append(keys, order)
+	}
+	sort.Ints(keys)
+
+	groups := [][]*ctx{}
+	for _, order := range keys {
+		groups = append(groups, orders[order])
+	}
+	return groups
+}
+
+// closes childs group by group, next group starts closing only when previous one is closed
+func (context *ctx) closeChilds(childs []*ctx) {
+	for _, group := range closingGroups(childs) {
+		if !context.tree.concurrentClosing {
+			for _, child := range group {
+				context.closeChild(child)
+			}
+			continue
+		}
+
+		closed := sync.WaitGroup{}
+		for _, child := range group {
+			if child.isPassive() { // passive child has no loop to wait for, it is closed in current goroutine while active siblings are closing
+				continue
+			}
+			closed.Add(1)
+			go func(child *ctx) {
+				defer closed.Done()
+				context.closeChild(child)
+			}(child)
+		}
+		for _, child := range group {
+			if child.isPassive() {
+				context.closeChild(child)
+			}
+		}
+		closed.Wait()
+	}
+}
+
+func (context *ctx) closeChild(child *ctx) {
+	child.recursiveClosing()
+	context.tree.changesAllowed.Lock()
+	delete(context.childs, child.id)
+	context.tree.changesAllowed.Unlock()
+}
+
+// waits till context loop exits, but not longer than grace period
+func (context *ctx) waitLoop() {
+	if context.isPassive() { // passive context has no loop, so there is nothing to wait for
+		return
+	}
+
+	gracePeriod := context.getGracePeriod()
+	if gracePeriod <= 0 {
+		context.loopWaitGroup.Wait()
+		return
+	}
+
+	finished := make(chan struct{})
+	go func() {
+		context.loopWaitGroup.Wait()
+		close(finished)
+	}()
+
+	expired := make(chan struct{})
+	timer := context.tree.clock.AfterFunc(gracePeriod, func() { close(expired) })
+	defer timer.Stop()
+
+	select {
+	case <-finished:
+	case <-expired:
+		context.forceDetach(gracePeriod)
+	}
+}
+
+// parent does not wait for stuck context anymore, its loop goroutine is left running
+func (context *ctx) forceDetach(gracePeriod time.Duration) {
+	context.LogEvent(LevelError, "not closed within grace period, detached", NewField("gracePeriod", gracePeriod.String()))
+
+	stuck := context.stuckNode()
+	stuck.Detached = true
+	stuck.GracePeriod = gracePeriod
+
+	context.tree.stuckMutex.Lock()
+	context.tree.stuck = append(context.tree.stuck, stuck)
+	context.tree.stuckMutex.Unlock()
+
+	context.countDetached()
+	context.releaseParent()
+}
+
+// parent childsWaitGroup is decremented only once, either by finished loop or by force detach
+func (context *ctx) releaseParent() {
+	if context.parent != nil {
+		context.parentReleased.Do(context.parent.childsWaitGroup.Done)
+	}
+}
+
+func (context *ctx) setFinished() {
+	context.closedMutex.Lock()
+	defer context.closedMutex.Unlock()
+	context.finished = true
+	close(context.done)
+}
+
+func (context *ctx) isFinished() bool {
+	context.closedMutex.Lock()
+	defer context.closedMutex.Unlock()
+	return context.finished
+}
+
+func (context *ctx) stuckNode() *StuckNode {
+	context.debuggerMutex.Lock()
+	defer context.debuggerMutex.Unlock()
+
+	names := []string{}
+	for _, node := range context.debuggerNodePath {
+		names = append(names, node.ComponentName)
+	}
+
+	node := context.debuggerNodePath[len(context.debuggerNodePath)-1]
+
+	return &StuckNode{
+		ID:            node.ID,
+		ComponentType: node.ComponentType,
+		ComponentName: node.ComponentName,
+		Path:          strings.Join(names, "->"),
+	}
+}
+
+// returns not finished contexts which are not waiting for their childs (they are the reason why parents are not closed)
+func (context *ctx) recursiveUnfinished() []*StuckNode {
+	stuck := []*StuckNode{}
+	for _, child := range context.childs {
+		stuck = append(stuck, child.recursiveUnfinished()...)
+	}
+	if len(stuck) == 0 && !context.isFinished() {
+		stuck = append(stuck, context.stuckNode())
+	}
+	return stuck
+}
+
+func (context *ctx) shutdownReport(completed bool, duration time.Duration) *ShutdownReport {
+	report := &ShutdownReport{
+		Completed: completed,
+		Duration:  duration,
+		Stuck:     []*StuckNode{},
+	}
+
+	context.tree.stuckMutex.Lock()
+	report.Stuck = append(report.Stuck, context.tree.stuck...)
+	context.tree.stuckMutex.Unlock()
+
+	if !completed {
+		context.tree.changesAllowed.Lock()
+		report.Stuck = append(report.Stuck, context.recursiveUnfinished()...)
+		context.tree.changesAllowed.Unlock()
+	}
+
+	return report
+}
@@ -0,0 +1,247 @@
+package context_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+type hungNode struct {
+	release chan struct{}
+}
+
+func (node *hungNode) Go(current context.Context) {
+	<-current.Opened()
+	<-node.release // ignores closing till it is released
+}
+
+type orderedNode struct {
+	name   string
+	closed *[]string
+	ready  *sync.Mutex
+}
+
+func (node *orderedNode) Go(current context.Context) {
+	<-current.Opened()
+	time.Sleep(10 * time.Millisecond)
+	node.ready.Lock()
+	*node.closed = append(*node.closed, node.name)
+	node.ready.Unlock()
+}
+
+func Test_GracePeriodDetachesHungChild(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+	root.SetGracePeriod(100 * time.Millisecond)
+
+	hung := &hungNode{release: make(chan struct{})}
+	defer close(hung.release)
+
+	if _, err := root.NewContextFor(hung, "hung", "node"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := root.NewContextFor(newNode(), "healthy", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	report := root.Shutdown(5 * time.Second)
+
+	if !report.Completed {
+		t.Fatal("shutdown is not completed")
+	}
+
+	if len(report.Stuck) != 1 || report.Stuck[0].Path != "root->hung" || !report.Stuck[0].Detached {
+		t.Fatalf("unexpected stuck nodes: %+v", report.Stuck)
+	}
+}
+
+func Test_GracePeriodWithClock(t *testing.T) {
+	clock := context.NewFakeClock(time.Unix(0, 0))
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+	root.SetGracePeriod(time.Minute)
+
+	hung := &hungNode{release: make(chan struct{})}
+	defer close(hung.release)
+
+	if _, err := root.NewContextFor(hung, "hung", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	reports := make(chan *context.ShutdownReport)
+	go func() {
+		reports <- root.Shutdown(time.Hour)
+	}()
+
+	waitTimers(t, clock, 2) // shutdown timeout and grace period of hung child
+	clock.Advance(time.Minute)
+
+	select {
+	case report := <-reports:
+		if !report.Completed || report.Duration != time.Minute {
+			t.Fatalf("unexpected report: %+v", report)
+		}
+		if len(report.Stuck) != 1 || !report.Stuck[0].Detached {
+			t.Fatalf("unexpected stuck nodes: %+v", report.Stuck)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("hung child is not detached after grace period of fake clock")
+	}
+}
+
+func Test_ShutdownTimeout(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	hung := &hungNode{release: make(chan struct{})}
+
+	parent, err := root.NewContextFor(newNode(), "parent", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parent.NewContextFor(hung, "hung", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	report := root.Shutdown(100 * time.Millisecond)
+
+	if report.Completed {
+		t.Fatal("shutdown completed with hung child")
+	}
+
+	if len(report.Stuck) != 1 || report.Stuck[0].Path != "root->parent->hung" || report.Stuck[0].Detached {
+		t.Fatalf("unexpected stuck nodes: %+v", report.Stuck)
+	}
+
+	close(hung.release)
+	root.Wait()
+}
+
+func Test_ClosingOrder(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	closed := []string{}
+	ready := &sync.Mutex{}
+
+	for i, name := range []string{"database", "listener", "cache"} {
+		ctx, err := root.NewContextFor(&orderedNode{name: name, closed: &closed, ready: ready}, name, "node")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx.SetClosingOrder([]int{2, 0, 1}[i])
+	}
+
+	report := root.Shutdown(5 * time.Second)
+	if !report.Completed || len(report.Stuck) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	ready.Lock()
+	defer ready.Unlock()
+
+	if len(closed) != 3 || closed[0] != "listener" || closed[1] != "cache" || closed[2] != "database" {
+		t.Fatalf("unexpected closing order: %v", closed)
+	}
+}
+
+func Test_SameClosingOrderClosedOneByOne(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	closed := []string{}
+	ready := &sync.Mutex{}
+
+	names := []string{"first", "second", "third", "fourth"}
+	for _, name := range names {
+		if _, err := root.NewContextFor(&orderedNode{name: name, closed: &closed, ready: ready}, name, "node"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report := root.Shutdown(5 * time.Second)
+	if !report.Completed || len(report.Stuck) != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	ready.Lock()
+	defer ready.Unlock()
+
+	for i, name := range names {
+		if len(closed) != len(names) || closed[i] != name {
+			t.Fatalf("siblings are not closed in order of creation: %v", closed)
+		}
+	}
+}
+
+// barrierNode exits only when all its siblings started closing
+type barrierNode struct {
+	arrived chan struct{}
+	proceed chan struct{}
+}
+
+func (node *barrierNode) Go(current context.Context) {
+	<-current.Opened()
+	node.arrived <- struct{}{}
+	<-node.proceed
+}
+
+func Test_ConcurrentClosing(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithConcurrentClosing())
+
+	arrived := make(chan struct{})
+	proceed := make(chan struct{})
+
+	for _, name := range []string{"first", "second"} {
+		if _, err := root.NewContextFor(&barrierNode{arrived: arrived, proceed: proceed}, name, "node"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := root.NewPassiveContext("passive", "passive"); err != nil {
+		t.Fatal(err)
+	}
+
+	root.Cancel()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-arrived:
+		case <-time.After(5 * time.Second):
+			t.Fatal("siblings are not closed simultaneously")
+		}
+	}
+	close(proceed)
+
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+// countingClock counts timers which are started with AfterFunc
+type countingClock struct {
+	context.Clock
+	timers int32
+}
+
+func (clock *countingClock) AfterFunc(duration time.Duration, handler func()) context.ClockTimer {
+	atomic.AddInt32(&clock.timers, 1)
+	return clock.Clock.AfterFunc(duration, handler)
+}
+
+func Test_GracePeriodNotWaitedForPassive(t *testing.T) {
+	clock := &countingClock{Clock: context.NewRealClock()}
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+	root.SetGracePeriod(time.Minute)
+
+	for _, name := range []string{"first", "second", "third"} {
+		if _, err := root.NewPassiveContext(name, "passive"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if report := root.Shutdown(time.Hour); !report.Completed {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if timers := atomic.LoadInt32(&clock.timers); timers != 2 { // shutdown timeout and grace period of root loop
+		t.Fatalf("expected 2 timers, %v were started", timers)
+	}
+}
@@ -0,0 +1,117 @@
+package context
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+)
+
+// SignalError is a cause of root context cancellation by OS signal
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (err *SignalError) Error() string {
+	return fmt.Sprintf("received %v signal", err.Signal)
+}
+
+// WithShutdownSignals cancels root context with SignalError on the first signal (SIGINT and SIGTERM if signals are not specified), the second one forces process exit
+func WithShutdownSignals(signals ...os.Signal) RootOption {
+	return func(options *rootOptions) {
+		if len(signals) == 0 {
+			signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+		}
+		options.shutdownSignals = signals
+	}
+}
+
+// WithReloadHandler calls handler on each SIGHUP signal
+func WithReloadHandler(handler func()) RootOption {
+	return func(options *rootOptions) {
+		options.reload = handler
+	}
+}
+
+// WithSignalSource replaces OS signals with signals from channel and os.Exit with exit function, it is intended for tests of WithShutdownSignals and WithReloadHandler
+func WithSignalSource(signals <-chan os.Signal, exit func(code int)) RootOption {
+	return func(options *rootOptions) {
+		options.signalSource = signals
+		options.exit = exit
+	}
+}
+
+func (root *Root) handleSignals(options *rootOptions) {
+	if len(options.shutdownSignals) == 0 && options.reload == nil {
+		return
+	}
+
+	signals, stop := options.signalSource, func() {}
+	if signals == nil {
+		signals, stop = notifySignals(options)
+	}
+
+	exit := options.exit
+	if exit == nil {
+		exit = os.Exit
+	}
+
+	go func() {
+		defer stop()
+
+		shuttingDown := false
+
+		for {
+			select {
+			case received := <-signals:
+				switch {
+				case received == syscall.SIGHUP && options.reload != nil:
+					root.LogEvent(LevelInfo, "reload signal", NewField("signal", received.String()))
+					root.reload(options.reload)
+				case !isOneOf(received, options.shutdownSignals): // source channel could send not subscribed signals
+				case !shuttingDown:
+					root.LogEvent(LevelWarning, "shutdown signal", NewField("signal", received.String()))
+					shuttingDown = true
+					root.CancelWithCause(&SignalError{Signal: received})
+				default:
+					root.LogEvent(LevelError, "shutdown signal received again, forcing exit", NewField("signal", received.String()))
+					exit(1)
+				}
+			case <-root.ctx.Done(): // root is closed, nothing to handle anymore
+				return
+			}
+		}
+	}()
+}
+
+// subscribes to OS signals, returned function unsubscribes
+func notifySignals(options *rootOptions) (<-chan os.Signal, func()) {
+	signals := make(chan os.Signal, 1)
+	if len(options.shutdownSignals) > 0 { // Notify without signals relays all of them
+		signal.Notify(signals, options.shutdownSignals...)
+	}
+	if options.reload != nil {
+		signal.Notify(signals, syscall.SIGHUP)
+	}
+	return signals, func() { signal.Stop(signals) }
+}
+
+func isOneOf(received os.Signal, signals []os.Signal) bool {
+	for _, current := range signals {
+		if current == received {
+			return true
+		}
+	}
+	return false
+}
+
+// reload handler panic is reported, but does not stop signals handling
+func (root *Root) reload(handler func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			root.LogEvent(LevelError, "reload handler panicked", NewField("panic", r), NewField("stack", string(debug.Stack())))
+		}
+	}()
+	handler()
+}
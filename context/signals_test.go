@@ -0,0 +1,116 @@
+package context_test
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_ShutdownSignals(t *testing.T) {
+	signals := make(chan os.Signal)
+	reloaded := make(chan struct{}, 1)
+
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true),
+		context.WithShutdownSignals(),
+		context.WithReloadHandler(func() {
+			reloaded <- struct{}{}
+		}),
+		context.WithSignalSource(signals, func(code int) {
+			t.Errorf("exit(%v) is called on the first shutdown signal", code)
+		}),
+	)
+
+	ctx, err := root.NewContextFor(newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signals <- syscall.SIGHUP
+
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reload handler is not called")
+	}
+
+	if ctx.Err() != nil {
+		t.Fatalf("context is canceled by reload signal: %v", ctx.Err())
+	}
+
+	signals <- syscall.SIGTERM
+
+	cause := root.Wait()
+
+	if signalError, ok := cause.(*context.SignalError); !ok || signalError.Signal != syscall.SIGTERM {
+		t.Fatalf("unexpected cause: %v", cause)
+	}
+}
+
+func Test_SecondShutdownSignalExits(t *testing.T) {
+	signals := make(chan os.Signal)
+	exits := make(chan int, 1)
+
+	hung := &hungNode{release: make(chan struct{})}
+	defer close(hung.release)
+
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true),
+		context.WithShutdownSignals(),
+		context.WithSignalSource(signals, func(code int) {
+			exits <- code
+		}),
+	)
+
+	if _, err := root.NewContextFor(hung, "hung", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	signals <- os.Interrupt
+
+	select {
+	case code := <-exits:
+		t.Fatalf("exit(%v) is called on the first shutdown signal", code)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	signals <- os.Interrupt
+
+	select {
+	case code := <-exits:
+		if code != 1 {
+			t.Fatalf("unexpected exit code %v", code)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("exit is not called on the second shutdown signal")
+	}
+}
+
+func Test_NotSubscribedSignalIgnored(t *testing.T) {
+	signals := make(chan os.Signal)
+
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true),
+		context.WithShutdownSignals(syscall.SIGTERM),
+		context.WithSignalSource(signals, func(code int) {}),
+	)
+
+	ctx, err := root.NewContextFor(newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signals <- syscall.SIGHUP // reload handler is not set
+	signals <- os.Interrupt
+
+	time.Sleep(50 * time.Millisecond)
+	if ctx.Err() != nil {
+		t.Fatalf("context is canceled by not subscribed signal: %v", ctx.Err())
+	}
+
+	signals <- syscall.SIGTERM
+
+	if _, ok := root.Wait().(*context.SignalError); !ok {
+		t.Fatal("root is not canceled by subscribed signal")
+	}
+}
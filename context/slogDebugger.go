@@ -0,0 +1,83 @@
+//go:build go1.21
+
+package context
+
+import (
+	stdContext "context"
+	"log/slog"
+	"strings"
+)
+
+// SlogDebugger forwards context events to slog.Logger, node path is added as "node" attributes group
+type SlogDebugger struct {
+	logger *slog.Logger
+}
+
+// NewSlogDebugger ...
+func NewSlogDebugger(logger *slog.Logger) Debugger {
+	return &SlogDebugger{
+		logger: logger,
+	}
+}
+
+// SlogLevel maps context level to slog level (trace events are one step below slog.LevelDebug)
+func SlogLevel(level Level) slog.Level {
+	switch {
+	case level <= LevelError:
+		return slog.LevelError
+	case level <= LevelWarning:
+		return slog.LevelWarn
+	case level <= LevelInfo:
+		return slog.LevelInfo
+	case level <= LevelDebug:
+		return slog.LevelDebug
+	default:
+		return slog.LevelDebug - 4
+	}
+}
+
+// Log ...
+func (slogDebugger *SlogDebugger) Log(nodePath []DebugNode, objects []interface{}) {
+	slogDebugger.LogEvent(nodePath, NewEventFromObjects(objects))
+}
+
+// LogEvent ...
+func (slogDebugger *SlogDebugger) LogEvent(nodePath []DebugNode, event *Event) {
+	level := SlogLevel(event.severity())
+	handler := slogDebugger.logger.Handler()
+
+	if !event.Unleveled && !handler.Enabled(stdContext.Background(), level) {
+		return
+	}
+
+	record := slog.NewRecord(event.Time, level, event.Message, 0)
+	record.AddAttrs(slogNodeGroup(nodePath))
+
+	for _, field := range event.Fields {
+		record.AddAttrs(slog.Any(field.Key, field.Value))
+	}
+
+	handler.Handle(stdContext.Background(), record)
+}
+
+func slogNodeGroup(nodePath []DebugNode) slog.Attr {
+	names := []string{}
+	ids := []int64{}
+
+	for _, node := range nodePath {
+		names = append(names, node.ComponentName)
+		ids = append(ids, node.ID)
+	}
+
+	node := DebugNode{}
+	if len(nodePath) > 0 {
+		node = nodePath[len(nodePath)-1]
+	}
+
+	return slog.Group("node",
+		slog.String("type", node.ComponentType),
+		slog.String("name", node.ComponentName),
+		slog.String("path", strings.Join(names, "->")),
+		slog.Any("ids", ids),
+	)
+}
@@ -0,0 +1,117 @@
+//go:build go1.21
+
+package context_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+type syncBuffer struct {
+	buffer bytes.Buffer
+	ready  sync.Mutex
+}
+
+func (buffer *syncBuffer) Write(data []byte) (int, error) {
+	buffer.ready.Lock()
+	defer buffer.ready.Unlock()
+	return buffer.buffer.Write(data)
+}
+
+func (buffer *syncBuffer) String() string {
+	buffer.ready.Lock()
+	defer buffer.ready.Unlock()
+	return buffer.buffer.String()
+}
+
+func Test_SlogDebugger(t *testing.T) {
+	output := &syncBuffer{}
+	logger := slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	root := context.NewRootContext(context.NewSlogDebugger(logger))
+
+	ctx, err := root.NewContextFor(newNode(), "child", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx.LogEvent(context.LevelWarning, "structured", context.NewField("key", "value"))
+
+	root.Cancel()
+	root.Wait()
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("debug events are not filtered by slog level:\n%v", output.String())
+	}
+
+	record := struct {
+		Level string `json:"level"`
+		Msg   string `json:"msg"`
+		Key   string `json:"key"`
+		Node  struct {
+			Type string  `json:"type"`
+			Name string  `json:"name"`
+			Path string  `json:"path"`
+			IDs  []int64 `json:"ids"`
+		} `json:"node"`
+	}{}
+
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Level != "WARN" || record.Msg != "structured" || record.Key != "value" || record.Node.Type != "node" || record.Node.Name != "child" || record.Node.Path != "root->child" || len(record.Node.IDs) != 2 {
+		t.Fatalf("unexpected slog record: %v", lines[0])
+	}
+}
+
+func Test_SlogDebuggerUnleveledNotFiltered(t *testing.T) {
+	output := &syncBuffer{}
+	logger := slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: slog.LevelError}))
+	debugger := context.NewSlogDebugger(logger)
+
+	nodePath := []context.DebugNode{{ID: 0, ComponentType: "root", ComponentName: "root"}}
+	debugger.Log(nodePath, []interface{}{"legacy message"})
+	debugger.Log(nodePath, []interface{}{int(context.LevelInfo), "filtered message"})
+
+	if !strings.Contains(output.String(), "legacy message") {
+		t.Fatalf("unleveled message is filtered: %q", output.String())
+	}
+	if strings.Contains(output.String(), "filtered message") {
+		t.Fatalf("leveled message is not filtered: %q", output.String())
+	}
+}
+
+func Test_SlogDebuggerLegacyLevels(t *testing.T) {
+	output := &syncBuffer{}
+	logger := slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	debugger := context.NewSlogDebugger(logger)
+
+	nodePath := []context.DebugNode{{ID: 0, ComponentType: "root", ComponentName: "root"}}
+	debugger.Log(nodePath, []interface{}{0, "zero"})
+	debugger.Log(nodePath, []interface{}{2, "two"})
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("legacy levels are not logged:\n%v", output.String())
+	}
+
+	for _, line := range lines {
+		record := struct {
+			Level string `json:"level"`
+		}{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		if record.Level != "INFO" {
+			t.Fatalf("legacy level is logged with %v level: %v", record.Level, line)
+		}
+	}
+}
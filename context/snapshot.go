@@ -0,0 +1,82 @@
+package context
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// NodeState ...
+type NodeState string
+
+const (
+	// NodeStateRunning context is not canceled
+	NodeStateRunning NodeState = "running"
+	// NodeStateClosing context is canceled, but its childs are still closing
+	NodeStateClosing NodeState = "closing"
+	// NodeStateClosed context Opened() channel is closed
+	NodeStateClosed NodeState = "closed"
+)
+
+// NodeSnapshot is a state of context node with all its childs at the moment of snapshot
+type NodeSnapshot struct {
+	ID            int64           `json:"id"`
+	ComponentType string          `json:"componentType"`
+	ComponentName string          `json:"componentName"`
+	State         NodeState       `json:"state"`
+	Cause         string          `json:"cause,omitempty"`
+	StartedAt     time.Time       `json:"startedAt"`
+	Uptime        time.Duration   `json:"uptimeNs"`
+	ChildsCount   int             `json:"childsCount"`
+	Childs        []*NodeSnapshot `json:"childs"`
+}
+
+// ToJSON ...
+func (snapshot *NodeSnapshot) ToJSON() ([]byte, error) {
+	return json.Marshal(snapshot)
+}
+
+func (context *ctx) state() NodeState {
+	if context.isClosed() {
+		return NodeStateClosed
+	}
+	if context.Cause() != nil {
+		return NodeStateClosing
+	}
+	return NodeStateRunning
+}
+
+func (context *ctx) snapshot() *NodeSnapshot {
+	context.tree.changesAllowed.Lock()
+	defer context.tree.changesAllowed.Unlock()
+	return context.recursiveSnapshot(time.Now())
+}
+
+func (context *ctx) recursiveSnapshot(now time.Time) *NodeSnapshot {
+	node := context.debuggerNode()
+
+	snapshot := &NodeSnapshot{
+		ID:            context.id,
+		ComponentType: node.ComponentType,
+		ComponentName: node.ComponentName,
+		State:         context.state(),
+		StartedAt:     context.startedAt,
+		Uptime:        now.Sub(context.startedAt),
+		ChildsCount:   len(context.childs),
+		Childs:        []*NodeSnapshot{},
+	}
+
+	if cause := context.Cause(); cause != nil {
+		snapshot.Cause = cause.Error()
+	}
+
+	for _, child := range context.childs {
+		snapshot.Childs = append(snapshot.Childs, child.recursiveSnapshot(now))
+	}
+
+	sort.Slice(snapshot.Childs, func(i int, j int) bool {
+		return snapshot.Childs[i].ID < snapshot.Childs[j].ID
+	})
+
+	return snapshot
+}
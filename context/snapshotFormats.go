@@ -0,0 +1,106 @@
+package context
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+var nodeStateColors = map[NodeState]string{
+	NodeStateRunning: "#66ff80",
+	NodeStateClosing: "#00bfff",
+	NodeStateClosed:  "#ff9999",
+}
+
+func (snapshot *NodeSnapshot) title() string {
+	return fmt.Sprintf("%v[%v] (%v)", snapshot.ComponentName, snapshot.ID, snapshot.ComponentType)
+}
+
+func (snapshot *NodeSnapshot) status() string {
+	status := fmt.Sprintf("%v %v", snapshot.State, snapshot.Uptime.Truncate(time.Millisecond))
+	if snapshot.Cause != "" {
+		status = fmt.Sprintf("%v: %v", status, snapshot.Cause)
+	}
+	return status
+}
+
+// ToText returns tree as indented text, one node per line
+func (snapshot *NodeSnapshot) ToText() string {
+	builder := &strings.Builder{}
+	snapshot.writeText(builder, 0)
+	return builder.String()
+}
+
+func (snapshot *NodeSnapshot) writeText(builder *strings.Builder, depth int) {
+	builder.WriteString(fmt.Sprintf("%v%v %v\n", strings.Repeat("  ", depth), snapshot.title(), snapshot.status()))
+	for _, child := range snapshot.Childs {
+		child.writeText(builder, depth+1)
+	}
+}
+
+// ToDOT returns tree in Graphviz DOT format
+func (snapshot *NodeSnapshot) ToDOT() string {
+	builder := &strings.Builder{}
+	builder.WriteString("digraph context {\n")
+	builder.WriteString("\tnode [shape=box, style=filled, fontname=Roboto];\n")
+	snapshot.writeDOT(builder, fmt.Sprintf("%v", snapshot.ID))
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func (snapshot *NodeSnapshot) writeDOT(builder *strings.Builder, path string) {
+	builder.WriteString(fmt.Sprintf("\t%q [label=%q, fillcolor=%q];\n", path, snapshot.title()+"\n"+snapshot.status(), nodeStateColors[snapshot.State]))
+	for _, child := range snapshot.Childs {
+		childPath := fmt.Sprintf("%v.%v", path, child.ID)
+		child.writeDOT(builder, childPath)
+		builder.WriteString(fmt.Sprintf("\t%q -> %q;\n", path, childPath))
+	}
+}
+
+const (
+	svgRowHeight = 30
+	svgIndent    = 30
+	svgBoxWidth  = 500
+	svgBoxHeight = 24
+)
+
+// ToSVG returns tree as SVG image, one node per row with state colors same as in schema.svg
+func (snapshot *NodeSnapshot) ToSVG() string {
+	body := &strings.Builder{}
+	rows := snapshot.writeSVG(body, 0, 0)
+	depth := snapshot.depth()
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" font-family=\"Roboto\" font-size=\"14px\">\n", (depth-1)*svgIndent+svgBoxWidth+10, rows*svgRowHeight+10))
+	builder.WriteString(body.String())
+	builder.WriteString("</svg>\n")
+	return builder.String()
+}
+
+// writes node with all its childs starting from row, returns number of written rows
+func (snapshot *NodeSnapshot) writeSVG(builder *strings.Builder, row int, depth int) int {
+	x := 5 + depth*svgIndent
+	y := 5 + row*svgRowHeight
+
+	builder.WriteString(fmt.Sprintf("<rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"%v\" stroke=\"#000\"/>\n", x, y, svgBoxWidth, svgBoxHeight, nodeStateColors[snapshot.State]))
+	builder.WriteString(fmt.Sprintf("<text x=\"%v\" y=\"%v\">%v %v</text>\n", x+5, y+17, html.EscapeString(snapshot.title()), html.EscapeString(snapshot.status())))
+
+	rows := 1
+	for _, child := range snapshot.Childs {
+		childY := 5 + (row+rows)*svgRowHeight + svgBoxHeight/2
+		builder.WriteString(fmt.Sprintf("<path d=\"M %v %v L %v %v L %v %v\" fill=\"none\" stroke=\"#000\"/>\n", x+svgIndent/2, y+svgBoxHeight, x+svgIndent/2, childY, x+svgIndent, childY))
+		rows += child.writeSVG(builder, row+rows, depth+1)
+	}
+	return rows
+}
+
+func (snapshot *NodeSnapshot) depth() int {
+	depth := 0
+	for _, child := range snapshot.Childs {
+		if childDepth := child.depth(); childDepth > depth {
+			depth = childDepth
+		}
+	}
+	return depth + 1
+}
@@ -0,0 +1,69 @@
+package context_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_Snapshot(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	parent, err := root.NewContextFor(newNode(), "parent", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"1", "2"} {
+		if _, err := parent.NewContextFor(newNode(), name, "node"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshot := root.Snapshot()
+
+	if snapshot.ComponentType != "root" || snapshot.State != context.NodeStateRunning || snapshot.ChildsCount != 1 {
+		t.Fatalf("unexpected root snapshot: %+v", snapshot)
+	}
+
+	parentSnapshot := snapshot.Childs[0]
+	if parentSnapshot.ComponentName != "parent" || parentSnapshot.ChildsCount != 2 {
+		t.Fatalf("unexpected parent snapshot: %+v", parentSnapshot)
+	}
+
+	if parentSnapshot.Childs[0].ComponentName != "1" || parentSnapshot.Childs[1].ComponentName != "2" {
+		t.Fatal("childs are not sorted by id")
+	}
+
+	data, err := snapshot.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := &context.NodeSnapshot{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Childs[0].Childs[1].ComponentName != "2" {
+		t.Fatalf("unexpected json snapshot: %v", string(data))
+	}
+
+	parent.Cancel()
+	select {
+	case <-parent.Opened():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context is not closed")
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); root.Snapshot().ChildsCount != 0; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("closed context is still in snapshot: %+v", root.Snapshot().Childs[0])
+		}
+	}
+
+	root.Cancel()
+	root.Wait()
+}
@@ -0,0 +1,65 @@
+package context
+
+import (
+	stdContext "context"
+	"errors"
+	"time"
+)
+
+// stdContextAdapter represents context node as standard library context.Context
+type stdContextAdapter struct {
+	context *ctx
+}
+
+// Deadline ...
+func (adapter *stdContextAdapter) Deadline() (time.Time, bool) {
+	return adapter.context.Deadline()
+}
+
+// Done returns channel what closes together with node Opened() channel
+func (adapter *stdContextAdapter) Done() <-chan struct{} {
+	return adapter.context.opened
+}
+
+// Err ...
+func (adapter *stdContextAdapter) Err() error {
+	select {
+	case <-adapter.context.opened:
+		if errors.Is(adapter.context.Cause(), stdContext.DeadlineExceeded) { // own deadline or deadline of bridged std context
+			return stdContext.DeadlineExceeded
+		}
+		return stdContext.Canceled
+	default:
+		return nil
+	}
+}
+
+// Value ...
+func (adapter *stdContextAdapter) Value(key interface{}) interface{} {
+	return adapter.context.Value(key)
+}
+
+// Std ...
+func (context *ctx) Std() stdContext.Context {
+	return &stdContextAdapter{context: context}
+}
+
+// NewContextForStd creates new child context which would be gracefully canceled when std context is done
+func (context *ctx) NewContextForStd(std stdContext.Context, instance ContextedInstance, componentName string, componentType string, options ...ChildOption) (Context, error) {
+
+	newContext, err := context.NewContextFor(instance, componentName, componentType, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	go func(std stdContext.Context, child Context) { // watcher exits when std is done or when child is closed by itself
+		select {
+		case <-std.Done():
+			child.LogEvent(LevelTrace, "std context done", NewField("error", std.Err()))
+			child.CancelWithCause(std.Err())
+		case <-child.Opened():
+		}
+	}(std, newContext)
+
+	return newContext, nil
+}
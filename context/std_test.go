@@ -0,0 +1,84 @@
+package context_test
+
+import (
+	stdContext "context"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_StdDoneOnCancel(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	ctx, err := root.NewContextFor(newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	std := ctx.Std()
+	if std.Err() != nil {
+		t.Fatal("std context is done before cancel")
+	}
+
+	root.Cancel()
+
+	select {
+	case <-std.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("std context is not done after cancel")
+	}
+
+	if std.Err() != stdContext.Canceled {
+		t.Fatalf("unexpected std context error: %v", std.Err())
+	}
+
+	root.Wait()
+}
+
+func Test_NewContextForStd(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	std, cancel := stdContext.WithCancel(stdContext.Background())
+
+	ctx, err := root.NewContextForStd(std, newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+
+	select {
+	case <-ctx.Opened():
+	case <-time.After(5 * time.Second):
+		t.Fatal("child context is not closed after std context canceled")
+	}
+
+	root.Cancel()
+	root.Wait()
+}
+
+func Test_StdDeadlineCauseBridged(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	std, cancel := stdContext.WithTimeout(stdContext.Background(), time.Millisecond)
+	defer cancel()
+
+	ctx, err := root.NewContextForStd(std, newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Opened():
+	case <-time.After(5 * time.Second):
+		t.Fatal("child context is not closed after std deadline exceeded")
+	}
+
+	if err := ctx.Std().Err(); err != stdContext.DeadlineExceeded {
+		t.Fatalf("std deadline of parent is reported as %v", err)
+	}
+
+	root.Cancel()
+	root.Wait()
+}
@@ -0,0 +1,289 @@
+package context
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SupervisorStrategy defines which childs are restarted when one of them fails
+type SupervisorStrategy int
+
+const (
+	// SupervisorStrategyOneForOne restarts only failed child
+	SupervisorStrategyOneForOne SupervisorStrategy = 0
+	// SupervisorStrategyOneForAll restarts all childs when one of them fails
+	SupervisorStrategyOneForAll SupervisorStrategy = 1
+	// SupervisorStrategyRestForOne restarts failed child and all childs added after it
+	SupervisorStrategyRestForOne SupervisorStrategy = 2
+)
+
+// UnexpectedExitError ...
+type UnexpectedExitError struct {
+	ComponentType string
+	ComponentName string
+}
+
+func (err *UnexpectedExitError) Error() string {
+	return fmt.Sprintf("%v[%v] exited from its loop without context canceling", err.ComponentType, err.ComponentName)
+}
+
+// TooManyRestartsError ...
+type TooManyRestartsError struct {
+	Restarts int
+	Window   time.Duration // zero for context restarted with FailurePolicyRestart, its restarts are not limited by window
+}
+
+func (err *TooManyRestartsError) Error() string {
+	if err.Window == 0 {
+		return fmt.Sprintf("context reached %v restarts", err.Restarts)
+	}
+	return fmt.Sprintf("supervisor reached %v restarts within %v", err.Restarts, err.Window)
+}
+
+// Supervisor ...
+type Supervisor interface {
+	ContextedInstance
+	AddChild(instance ContextedInstance, componentName string, componentType string) Supervisor // add child which would be started with supervisor (add all childs before supervisor context is created)
+	SetBackoff(minDelay time.Duration, maxDelay time.Duration) Supervisor                       // restart delay starts from minDelay and doubles for each next restart within window till maxDelay
+	SetRestartsLimit(maxRestarts int, window time.Duration) Supervisor                          // supervisor fails with TooManyRestartsError when childs restarted more than maxRestarts times within window (0 is unlimited)
+}
+
+type supervisor struct {
+	strategy    SupervisorStrategy
+	minDelay    time.Duration
+	maxDelay    time.Duration
+	maxRestarts int
+	window      time.Duration
+	childs      []*supervisedChild
+	restarts    []time.Time
+
+	ready sync.Mutex
+}
+
+type supervisedChild struct {
+	supervisor    *supervisor
+	index         int
+	instance      ContextedInstance
+	componentName string
+	componentType string
+	context       Context   // nil if child is not running
+	restartAt     time.Time // zero if restart is not scheduled
+}
+
+// supervisedRun is a single start of supervised child, it reports child exit to the supervisor loop which started it
+type supervisedRun struct {
+	child *supervisedChild
+	exits chan<- *supervisedExit
+	done  <-chan struct{} // closed when supervisor loop is finished and nobody reads exits anymore
+}
+
+type supervisedExit struct {
+	child   *supervisedChild
+	failure error
+}
+
+// NewSupervisor ...
+func NewSupervisor(strategy SupervisorStrategy) Supervisor {
+	return &supervisor{
+		strategy:    strategy,
+		minDelay:    DefaultRestartMinDelay,
+		maxDelay:    DefaultRestartMaxDelay,
+		maxRestarts: 10,
+		window:      time.Minute,
+		childs:      []*supervisedChild{},
+		restarts:    []time.Time{},
+	}
+}
+
+// AddChild ...
+func (supervisor *supervisor) AddChild(instance ContextedInstance, componentName string, componentType string) Supervisor {
+	supervisor.ready.Lock()
+	defer supervisor.ready.Unlock()
+
+	supervisor.childs = append(supervisor.childs, &supervisedChild{
+		supervisor:    supervisor,
+		index:         len(supervisor.childs),
+		instance:      instance,
+		componentName: componentName,
+		componentType: componentType,
+	})
+	return supervisor
+}
+
+// SetBackoff ...
+func (supervisor *supervisor) SetBackoff(minDelay time.Duration, maxDelay time.Duration) Supervisor {
+	supervisor.ready.Lock()
+	defer supervisor.ready.Unlock()
+	supervisor.minDelay = minDelay
+	supervisor.maxDelay = maxDelay
+	return supervisor
+}
+
+// SetRestartsLimit ...
+func (supervisor *supervisor) SetRestartsLimit(maxRestarts int, window time.Duration) Supervisor {
+	supervisor.ready.Lock()
+	defer supervisor.ready.Unlock()
+	supervisor.maxRestarts = maxRestarts
+	supervisor.window = window
+	return supervisor
+}
+
+// Go ...
+func (supervisor *supervisor) Go(current Context) {
+	exits := make(chan *supervisedExit)
+	restart := make(chan struct{}, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	supervisor.ready.Lock()
+	for _, child := range supervisor.childs {
+		supervisor.startChild(current, child, exits, done)
+	}
+	supervisor.ready.Unlock()
+
+	var restartTimer ClockTimer
+	defer func() {
+		if restartTimer != nil {
+			restartTimer.Stop()
+		}
+	}()
+
+loop:
+	for {
+		if restartTimer != nil { // nearest restart could be changed, so timer is scheduled again on each iteration
+			restartTimer.Stop()
+		}
+
+		supervisor.ready.Lock()
+		restartTimer = supervisor.nextRestartTimer(current.Clock(), restart)
+		supervisor.ready.Unlock()
+
+		select {
+		case exit := <-exits:
+			supervisor.ready.Lock()
+			exit.child.context = nil
+			if exit.failure != nil && current.Err() == nil {
+				supervisor.onChildFailure(current, exit.child, exit.failure)
+			}
+			supervisor.ready.Unlock()
+			break
+		case <-restart:
+			supervisor.ready.Lock()
+			now := current.Clock().Now()
+			for _, child := range supervisor.childs {
+				if child.context == nil && !child.restartAt.IsZero() && !now.Before(child.restartAt) {
+					current.LogEvent(LevelInfo, "restarting", NewField("componentType", child.componentType), NewField("componentName", child.componentName))
+					supervisor.startChild(current, child, exits, done)
+				}
+			}
+			supervisor.ready.Unlock()
+			break
+		case _, opened := <-current.Opened():
+			if !opened {
+				break loop
+			}
+		}
+	}
+}
+
+func (supervisor *supervisor) startChild(current Context, child *supervisedChild, exits chan<- *supervisedExit, done <-chan struct{}) {
+	child.restartAt = time.Time{}
+
+	childContext, err := current.NewContextFor(&supervisedRun{child: child, exits: exits, done: done}, child.componentName, child.componentType, WithFailurePolicy(FailurePolicyIgnore)) // failure is handled by supervisor, not by its parent
+	if err != nil {
+		current.LogEvent(LevelError, "could not start", NewField("componentType", child.componentType), NewField("componentName", child.componentName), NewField("error", err.Error()))
+		return
+	}
+	child.context = childContext
+}
+
+func (supervisor *supervisor) onChildFailure(current Context, child *supervisedChild, failure error) {
+	current.LogEvent(LevelError, "child failed", NewField("componentType", child.componentType), NewField("componentName", child.componentName), NewField("cause", failure.Error()))
+
+	now := current.Clock().Now()
+
+	{ // circuit breaker counts only restarts within window
+		restarts := []time.Time{}
+		for _, restart := range supervisor.restarts {
+			if now.Sub(restart) < supervisor.window {
+				restarts = append(restarts, restart)
+			}
+		}
+		supervisor.restarts = append(restarts, now)
+
+		if supervisor.maxRestarts > 0 && len(supervisor.restarts) > supervisor.maxRestarts {
+			current.fail(&TooManyRestartsError{Restarts: supervisor.maxRestarts, Window: supervisor.window})
+			return
+		}
+	}
+
+	delay := supervisor.minDelay
+	for i := 1; i < len(supervisor.restarts) && delay < supervisor.maxDelay; i++ {
+		delay = delay * 2
+	}
+	if delay > supervisor.maxDelay {
+		delay = supervisor.maxDelay
+	}
+
+	restarting := []*supervisedChild{child}
+	switch supervisor.strategy {
+	case SupervisorStrategyOneForAll:
+		restarting = supervisor.childs
+	case SupervisorStrategyRestForOne:
+		restarting = supervisor.childs[child.index:]
+	}
+
+	for _, restartingChild := range restarting {
+		current.LogEvent(LevelInfo, "restart scheduled", NewField("componentType", restartingChild.componentType), NewField("componentName", restartingChild.componentName), NewField("delay", delay.String()))
+		restartingChild.restartAt = now.Add(delay)
+		if restartingChild.context != nil {
+			restartingChild.context.Cancel() // restart would happen only after child exit
+		}
+	}
+}
+
+// returns nil if there are no scheduled restarts for stopped childs, otherwise timer signals restart channel at the nearest restart time
+func (supervisor *supervisor) nextRestartTimer(clock Clock, restart chan<- struct{}) ClockTimer {
+	nearest := time.Time{}
+	for _, child := range supervisor.childs {
+		if child.context == nil && !child.restartAt.IsZero() {
+			if nearest.IsZero() || child.restartAt.Before(nearest) {
+				nearest = child.restartAt
+			}
+		}
+	}
+	if nearest.IsZero() {
+		return nil
+	}
+	return clock.AfterFunc(nearest.Sub(clock.Now()), func() {
+		select {
+		case restart <- struct{}{}:
+		default: // restart is already signaled
+		}
+	})
+}
+
+// Go runs supervised instance, its panic is handled by context as any other failure, so exit is reported from closed handler
+func (run *supervisedRun) Go(current Context) {
+	current.AddOnClosed(run.reportExit)
+
+	run.child.instance.Go(current)
+
+	if current.Err() == nil {
+		current.CancelWithCause(&UnexpectedExitError{ComponentType: run.child.componentType, ComponentName: run.child.componentName})
+		<-current.Opened() // childs of failed instance have to be closed before exit
+	}
+}
+
+func (run *supervisedRun) reportExit(closed Context) {
+	failure := closed.Cause()
+	if _, canceled := failure.(*CanceledError); canceled {
+		failure = nil
+	}
+
+	select {
+	case run.exits <- &supervisedExit{child: run.child, failure: failure}:
+	case <-run.done:
+	}
+}
@@ -0,0 +1,170 @@
+package context_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_SupervisorOneForOne(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	failing := newPanicNode()
+	stable := newPanicNode()
+
+	supervisor := context.NewSupervisor(context.SupervisorStrategyOneForOne).
+		SetBackoff(time.Millisecond, 10*time.Millisecond).
+		AddChild(failing, "failing", "node").
+		AddChild(stable, "stable", "node")
+
+	if _, err := root.NewContextFor(supervisor, "supervisor", "supervisor"); err != nil {
+		t.Fatal(err)
+	}
+
+	waitStarted(t, stable)
+	for i := 0; i < 3; i++ {
+		waitStarted(t, failing)
+		failing.trigger <- struct{}{}
+	}
+	waitStarted(t, failing)
+
+	if len(stable.started) != 0 {
+		t.Fatal("not failed child is restarted")
+	}
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_SupervisorOneForAll(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	failing := newPanicNode()
+	stable := newPanicNode()
+
+	supervisor := context.NewSupervisor(context.SupervisorStrategyOneForAll).
+		SetBackoff(time.Millisecond, 10*time.Millisecond).
+		AddChild(stable, "stable", "node").
+		AddChild(failing, "failing", "node")
+
+	if _, err := root.NewContextFor(supervisor, "supervisor", "supervisor"); err != nil {
+		t.Fatal(err)
+	}
+
+	waitStarted(t, stable)
+	waitStarted(t, failing)
+
+	failing.trigger <- struct{}{}
+
+	waitStarted(t, stable)
+	waitStarted(t, failing)
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_SupervisorRestartsLimit(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	failing := newPanicNode()
+
+	supervisor := context.NewSupervisor(context.SupervisorStrategyOneForOne).
+		SetBackoff(time.Millisecond, 10*time.Millisecond).
+		SetRestartsLimit(2, time.Minute).
+		AddChild(failing, "failing", "node")
+
+	if _, err := root.NewContextFor(supervisor, "supervisor", "supervisor"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		waitStarted(t, failing)
+		failing.trigger <- struct{}{}
+	}
+
+	var tooManyRestarts *context.TooManyRestartsError
+	if err := root.Wait(); !errors.As(err, &tooManyRestarts) {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_SupervisorBackoffWithClock(t *testing.T) {
+	clock := context.NewFakeClock(time.Unix(0, 0))
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+
+	failing := newPanicNode()
+
+	supervisor := context.NewSupervisor(context.SupervisorStrategyOneForOne).
+		SetBackoff(time.Second, 2*time.Second).
+		AddChild(failing, "failing", "node")
+
+	if _, err := root.NewContextFor(supervisor, "supervisor", "supervisor"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, delay := range []time.Duration{time.Second, 2 * time.Second, 2 * time.Second} {
+		waitStarted(t, failing)
+		failing.trigger <- struct{}{}
+
+		waitTimers(t, clock, 1)
+		clock.Advance(delay - time.Millisecond)
+		notStarted(t, failing)
+		clock.Advance(time.Millisecond)
+	}
+	waitStarted(t, failing)
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
+
+func Test_SupervisedPanicIsCounted(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+
+	panics := make(chan *context.LifecycleEvent, 16)
+	root.AddObserver(context.ObserverFunc(func(event *context.LifecycleEvent) {
+		if event.Type == context.LifecyclePanic {
+			panics <- event
+		}
+	}), 0)
+
+	failing := newPanicNode()
+
+	supervisor := context.NewSupervisor(context.SupervisorStrategyOneForOne).
+		SetBackoff(time.Millisecond, time.Millisecond).
+		AddChild(failing, "failing", "supervisedNode")
+
+	if _, err := root.NewContextFor(supervisor, "supervisor", "supervisor"); err != nil {
+		t.Fatal(err)
+	}
+
+	waitStarted(t, failing)
+	failing.trigger <- struct{}{}
+	waitStarted(t, failing)
+
+	select {
+	case event := <-panics:
+		var panicError *context.PanicError
+		if !errors.As(event.Cause, &panicError) {
+			t.Fatalf("panic event has unexpected cause: %v", event.Cause)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("panic of supervised child is not observed")
+	}
+
+	if panics := root.Metrics().Components["supervisedNode"].Panics; panics != 1 {
+		t.Fatalf("expected 1 panic in metrics, got %v", panics)
+	}
+
+	root.Cancel()
+	if err := root.Wait(); err != nil {
+		t.Fatalf("root returned unexpected cause: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package context
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Span of context node lifetime, it starts with node and ends when node loop finished
+type Span interface {
+	End(cause error) // cause is nil if instance exited from loop without context canceling
+}
+
+// Tracer opens spans for context nodes, it could be adapter to OpenTelemetry or any other tracing system
+type Tracer interface {
+	StartSpan(parent Span, name string, attributes []Field) Span // parent is nil for root context
+}
+
+// WithTracer opens span for each context of the tree
+func WithTracer(tracer Tracer) RootOption {
+	return func(options *rootOptions) {
+		options.tracer = tracer
+	}
+}
+
+func (context *ctx) startSpan() {
+	if context.tree.tracer == nil {
+		return
+	}
+
+	var parentSpan Span
+	if context.parent != nil {
+		parentSpan = context.parent.span
+	}
+
+	path := context.Path()
+	names := []string{}
+	for _, node := range path {
+		names = append(names, node.ComponentName)
+	}
+	node := path[len(path)-1]
+
+	context.span = context.tree.tracer.StartSpan(parentSpan, node.ComponentName, []Field{
+		NewField("component.name", node.ComponentName),
+		NewField("component.type", node.ComponentType),
+		NewField("context.id", node.ID),
+		NewField("context.path", strings.Join(names, "->")),
+	})
+}
+
+func (context *ctx) endSpan() {
+	if context.span != nil {
+		context.span.End(context.Cause())
+	}
+}
+
+// RecordedSpan ...
+type RecordedSpan struct {
+	ID         int64
+	ParentID   int64 // zero for root span
+	Name       string
+	Attributes []Field
+	StartTime  time.Time
+	EndTime    time.Time // zero till span is not ended
+	Cause      error
+	tracer     *InMemoryTracer
+}
+
+// End ...
+func (span *RecordedSpan) End(cause error) {
+	span.tracer.ready.Lock()
+	defer span.tracer.ready.Unlock()
+	span.EndTime = time.Now()
+	span.Cause = cause
+}
+
+// Attribute returns attribute value or nil if there is no such attribute
+func (span *RecordedSpan) Attribute(key string) interface{} {
+	for _, attribute := range span.Attributes {
+		if attribute.Key == key {
+			return attribute.Value
+		}
+	}
+	return nil
+}
+
+func (span *RecordedSpan) String() string {
+	return fmt.Sprintf("%v[%v] parent=%v", span.Name, span.ID, span.ParentID)
+}
+
+// InMemoryTracer keeps all spans in memory, it is intended for tests
+type InMemoryTracer struct {
+	spans  []*RecordedSpan
+	nextID int64
+	ready  sync.Mutex
+}
+
+// NewInMemoryTracer ...
+func NewInMemoryTracer() *InMemoryTracer {
+	return &InMemoryTracer{
+		spans:  []*RecordedSpan{},
+		nextID: 1,
+	}
+}
+
+// StartSpan ...
+func (tracer *InMemoryTracer) StartSpan(parent Span, name string, attributes []Field) Span {
+	tracer.ready.Lock()
+	defer tracer.ready.Unlock()
+
+	span := &RecordedSpan{
+		ID:         tracer.nextID,
+		Name:       name,
+		Attributes: attributes,
+		StartTime:  time.Now(),
+		tracer:     tracer,
+	}
+	tracer.nextID++
+
+	if parentSpan, ok := parent.(*RecordedSpan); ok {
+		span.ParentID = parentSpan.ID
+	}
+
+	tracer.spans = append(tracer.spans, span)
+	return span
+}
+
+// Spans returns copies of all started spans in order of start
+func (tracer *InMemoryTracer) Spans() []*RecordedSpan {
+	tracer.ready.Lock()
+	defer tracer.ready.Unlock()
+
+	spans := []*RecordedSpan{}
+	for _, span := range tracer.spans {
+		copied := *span
+		spans = append(spans, &copied)
+	}
+	return spans
+}
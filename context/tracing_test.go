@@ -0,0 +1,47 @@
+package context_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+func Test_TracingSpans(t *testing.T) {
+	tracer := context.NewInMemoryTracer()
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithTracer(tracer))
+
+	parent, err := root.NewContextFor(newNode(), "parent", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := parent.NewContextFor(newNode(), "child", "plugin"); err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := errors.New("stopped")
+	root.CancelWithCause(stopped)
+	root.Wait()
+
+	spans := tracer.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("unexpected spans: %v", spans)
+	}
+
+	rootSpan, parentSpan, childSpan := spans[0], spans[1], spans[2]
+
+	if rootSpan.ParentID != 0 || parentSpan.ParentID != rootSpan.ID || childSpan.ParentID != parentSpan.ID {
+		t.Fatalf("unexpected spans hierarchy: %v", spans)
+	}
+
+	if childSpan.Name != "child" || childSpan.Attribute("component.type") != "plugin" || childSpan.Attribute("context.path") != "root->parent->child" {
+		t.Fatalf("unexpected child span attributes: %+v", childSpan.Attributes)
+	}
+
+	for _, span := range spans {
+		if span.EndTime.IsZero() || span.Cause != stopped {
+			t.Fatalf("span %v is not ended with cause: %v", span, span.Cause)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package context
+
+import (
+	"fmt"
+)
+
+// WithValue stores value in current context, it would be available with Value() for current context and all its childs
+func (context *ctx) WithValue(key interface{}, value interface{}) Context {
+	context.valuesMutex.Lock()
+	defer context.valuesMutex.Unlock()
+	if context.values == nil { // most of contexts have no values, so map is created on demand
+		context.values = make(map[interface{}]interface{})
+	}
+	context.values[key] = value
+	return context
+}
+
+// Value returns value stored in current or the nearest parent context, nil if there is no value for key
+func (context *ctx) Value(key interface{}) interface{} {
+	for current := context; current != nil; current = current.parent {
+		current.valuesMutex.Lock()
+		value, found := current.values[key]
+		current.valuesMutex.Unlock()
+		if found {
+			return value
+		}
+	}
+	return nil
+}
+
+// setLoggedValues selects values which would be added as fields to every event logged by context where they are available
+func (context *ctx) setLoggedValues(keys []interface{}) {
+	context.tree.loggedKeysMutex.Lock()
+	defer context.tree.loggedKeysMutex.Unlock()
+	context.tree.loggedKeys = keys
+}
+
+func (context *ctx) loggedValues() []Field {
+	context.tree.loggedKeysMutex.Lock()
+	keys := context.tree.loggedKeys
+	context.tree.loggedKeysMutex.Unlock()
+
+	fields := []Field{}
+	for _, key := range keys {
+		if value := context.Value(key); value != nil {
+			fields = append(fields, NewField(fmt.Sprintf("%v", key), value))
+		}
+	}
+	return fields
+}
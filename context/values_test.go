@@ -0,0 +1,82 @@
+package context_test
+
+import (
+	"testing"
+
+	"github.com/mcfly722/goPackages/context"
+)
+
+type requestIDKey struct{}
+
+func Test_ValuesInheritance(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+	root.WithValue("tenant", "root tenant")
+
+	parent, err := root.NewContextFor(newNode(), "parent", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent.WithValue(requestIDKey{}, "42")
+
+	child, err := parent.NewContextFor(newNode(), "child", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	child.WithValue("tenant", "child tenant")
+
+	if value := child.Value(requestIDKey{}); value != "42" {
+		t.Fatalf("child has not inherited parent value: %v", value)
+	}
+
+	if value := child.Value("tenant"); value != "child tenant" {
+		t.Fatalf("child value is not overridden: %v", value)
+	}
+
+	if value := parent.Value("tenant"); value != "root tenant" {
+		t.Fatalf("parent has not inherited root value: %v", value)
+	}
+
+	if value := root.Value(requestIDKey{}); value != nil {
+		t.Fatalf("root sees child value: %v", value)
+	}
+
+	if value := child.Std().Value(requestIDKey{}); value != "42" {
+		t.Fatalf("std context does not return value: %v", value)
+	}
+
+	root.Cancel()
+	root.Wait()
+}
+
+func Test_LoggedValues(t *testing.T) {
+	debugger := &recordingDebugger{}
+	root := context.NewRootContext(debugger)
+	root.SetLoggedValues("requestID")
+
+	ctx, err := root.NewContextFor(newNode(), "0", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx.WithValue("requestID", "42")
+
+	child, err := ctx.NewContextFor(newNode(), "1", "node")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	child.LogEvent(context.LevelInfo, "with value", context.NewField("key", "value"))
+	root.LogEvent(context.LevelInfo, "without value")
+
+	event := debugger.find("with value")
+	if event == nil || len(event.Fields) != 2 || event.Fields[0].Key != "requestID" || event.Fields[0].Value != "42" || event.Fields[1].Key != "key" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+
+	event = debugger.find("without value")
+	if event == nil || len(event.Fields) != 0 {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+
+	root.Cancel()
+	root.Wait()
+}
@@ -14,7 +14,7 @@ type Console struct {
 
 // Log ...
 func (console *Console) Log(msg string) {
-	console.context.Log(50, msg)
+	console.context.LogEvent(context.LevelInfo, msg)
 }
 
 // Constructor ...
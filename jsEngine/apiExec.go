@@ -41,7 +41,6 @@ type Process struct {
 type process struct {
 	exec                 *Exec
 	command              *exec.Cmd
-	expiredAt            time.Time
 	exitCode             int
 	finish               chan struct{}
 	stdoutStrings        chan string
@@ -156,10 +155,10 @@ func (command *Command) Start() *Process {
 	}
 
 	if command.timeout != 0 {
-		started.process.expiredAt = time.Now().Add(command.timeout)
+		_, err = command.exec.context.NewContextWithTimeout(command.timeout, started.process, command.name, "process")
+	} else {
+		_, err = command.exec.context.NewContextFor(started.process, command.name, "process")
 	}
-
-	_, err = command.exec.context.NewContextFor(started.process, command.name, "process")
 	if err != nil {
 		panic(command.exec.runtime.ToValue(err.Error()))
 	}
@@ -207,16 +206,6 @@ loop:
 				}
 			}
 			break
-
-		default:
-			if !process.expiredAt.IsZero() {
-				if time.Now().After(process.expiredAt) {
-					process.expiredAt = time.Time{} // to do not call several cancels on expiration
-
-					current.Log(50, "timeouted")
-					current.Cancel()
-				}
-			}
 		}
 	}
 
@@ -226,7 +215,7 @@ loop:
 
 	if err := process.command.Process.Kill(); err != nil {
 		if !errors.Is(err, syscall.EINVAL) {
-			current.Log(50, "killing process", err.Error())
+			current.LogEvent(context.LevelWarning, "killing process", context.NewField("error", err.Error()))
 		}
 	}
 
@@ -1,8 +1,7 @@
 package jsEngine_test
 
 import (
-	"os"
-	"os/signal"
+	"runtime"
 	"testing"
 	"time"
 
@@ -17,46 +16,49 @@ func ExampleExec() {
 	eventLoop.Import(jsEngine.Exec{})
 }
 
+// long running command which prints something to stdout
+func pingCommand() string {
+	if runtime.GOOS == "windows" {
+		return `"ping.exe", ["-n","30", "127.0.0.1"]`
+	}
+	return `"sh", ["-c", "echo started; exec sleep 30"]`
+}
+
 func Test_ExecProcess(t *testing.T) {
 
 	script := jsEngine.NewScript("test", `
 		function onDone(exitCode){
 				Console.Log("exitCode="+exitCode)
+				done(exitCode)
 		}
 		function onStdout(content){
 				Console.Log("content="+content)
 		}
-		p = Exec.NewCommand("ping.exe", ["-n","2", "0.0.0.0"]).SetTimeoutMs(30*1000).SetOnDone(onDone).SetOnStdoutString(onStdout).SetPath('C:/Users').Start()
+		p = Exec.NewCommand(`+pingCommand()+`).SetTimeoutMs(30*1000).SetOnDone(onDone).SetOnStdoutString(onStdout).Start()
 
 	`)
 
-	rootContext := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+	clock := context.NewFakeClock(time.Now())
 
+	rootContext := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithShutdownSignals(), context.WithClock(clock)) // ctrl+c gracefully shutdowns context
+
+	done := newDoneModule()
 	eventLoop := jsEngine.NewEventLoop(goja.New(), []jsEngine.Script{script})
 
 	eventLoop.Import(jsEngine.Console{})
 	eventLoop.Import(jsEngine.Scheduler{})
 	eventLoop.Import(jsEngine.Exec{})
+	eventLoop.Import(done)
 
 	rootContext.NewContextFor(eventLoop, "jsEngine", "eventLoop")
 
-	{ // handle ctrl+c for gracefully shutdown using context
-		ctrlC := make(chan os.Signal, 1)
-		signal.Notify(ctrlC, os.Interrupt)
-		go func() {
-			<-ctrlC
-			rootContext.Log(3, "CTRL+C signal")
-			rootContext.Cancel()
-		}()
-	}
+	clock.WaitTimers(1) // process is started with timeout
+	clock.Advance(30 * time.Second)
 
-	{ // wait 5 second till exit
-		go func() {
-			time.Sleep(10 * time.Second)
-			rootContext.Log(1, "timeout")
-			rootContext.Cancel()
-		}()
+	if exitCode := done.wait(t).ToInteger(); exitCode != -1 {
+		t.Fatalf("process is not killed by timeout, exitCode=%v", exitCode)
 	}
 
+	rootContext.Cancel()
 	rootContext.Wait()
 }
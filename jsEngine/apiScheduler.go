@@ -90,7 +90,8 @@ func (ticker *Ticker) Start() *StartedTicker {
 }
 
 func (ticker *activeTicker) Go(current context.Context) {
-	delay := time.Duration(time.Duration(ticker.spreadMs) * time.Millisecond)
+	clock := current.Clock()
+	tick := clock.After(time.Duration(time.Duration(ticker.spreadMs) * time.Millisecond))
 loop:
 	for {
 		select {
@@ -104,13 +105,13 @@ loop:
 				current.Cancel()
 			}
 			break
-		case <-time.After(delay):
-			delay = time.Duration(time.Duration(ticker.intervalMs) * time.Millisecond)
+		case <-tick:
 			_, err := ticker.scheduler.eventLoop.CallHandler(ticker.handler)
 			if err != nil {
-				current.Log(51, err.Error())
-				current.Cancel()
+				current.LogEvent(context.LevelError, "ticker handler", context.NewField("error", err.Error()))
+				current.CancelWithCause(err)
 			}
+			tick = clock.After(time.Duration(time.Duration(ticker.intervalMs) * time.Millisecond)) // next interval starts when handler returned
 			break
 		}
 	}
@@ -1,8 +1,6 @@
 package jsEngine_test
 
 import (
-	"os"
-	"os/signal"
 	"testing"
 	"time"
 
@@ -29,6 +27,7 @@ func Test_SetInterval(t *testing.T) {
 			if (count>4) {
 				Console.Log("stop")
 				ticker.Stop()
+				done(count)
 			} else {
 				Console.Log("timer"+count)
 			}
@@ -36,32 +35,31 @@ func Test_SetInterval(t *testing.T) {
     }).SetInitialSpread(10).Start()
 	`)
 
-	rootContext := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+	clock := context.NewFakeClock(time.Now())
 
+	rootContext := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithShutdownSignals(), context.WithClock(clock)) // ctrl+c gracefully shutdowns context
+
+	done := newDoneModule()
 	eventLoop := jsEngine.NewEventLoop(goja.New(), []jsEngine.Script{script})
 
 	eventLoop.Import(jsEngine.Console{})
 	eventLoop.Import(jsEngine.Scheduler{})
+	eventLoop.Import(done)
 
 	rootContext.NewContextFor(eventLoop, "jsEngine", "eventLoop")
 
-	{ // handle ctrl+c for gracefully shutdown using context
-		ctrlC := make(chan os.Signal, 1)
-		signal.Notify(ctrlC, os.Interrupt)
-		go func() {
-			<-ctrlC
-			rootContext.Log(2, "CTRL+C signal")
-			rootContext.Cancel()
-		}()
+	clock.WaitTimers(1) // ticker is waiting for initial spread
+	clock.Advance(10 * time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		clock.WaitTimers(1) // handler returned and ticker is waiting for next interval
+		clock.Advance(time.Second)
 	}
 
-	{ // wait 10 second till exit
-		go func() {
-			time.Sleep(10 * time.Second)
-			rootContext.Log(1, "timeout")
-			rootContext.Cancel()
-		}()
+	if count := done.wait(t).ToInteger(); count != 5 {
+		t.Fatalf("ticker stopped after %v calls", count)
 	}
 
+	rootContext.Cancel()
 	rootContext.Wait()
 }
@@ -1,8 +1,6 @@
 package jsEngine
 
 import (
-	"fmt"
-
 	"github.com/dop251/goja"
 	"github.com/mcfly722/goPackages/context"
 )
@@ -91,8 +89,8 @@ func (eventLoop *eventLoop) Go(current context.Context) {
 	for _, script := range eventLoop.scripts {
 		_, err := eventLoop.runtime.RunString(script.getBody())
 		if err != nil {
-			current.Log(1, fmt.Sprintf("%v: %v", script.getName(), err.Error()))
-			current.Cancel()
+			current.LogEvent(context.LevelError, "script", context.NewField("name", script.getName()), context.NewField("error", err.Error()))
+			current.CancelWithCause(err)
 		}
 	}
 
@@ -2,8 +2,6 @@ package jsEngine_test
 
 import (
 	"fmt"
-	"os"
-	"os/signal"
 	"testing"
 	"time"
 
@@ -29,39 +27,54 @@ func (module testModule) Constructor(context context.Context, eventLoop jsEngine
 	runtime.Set("handle", handle)
 }
 
-func testScript(scriptBody string, timeoutSec int) {
+// doneModule lets script signal that it reached expected point, so tests wait for it instead of sleeping
+type doneModule struct {
+	done chan goja.Value
+}
+
+func newDoneModule() *doneModule {
+	return &doneModule{done: make(chan goja.Value, 16)}
+}
+
+func (module *doneModule) Constructor(context context.Context, eventLoop jsEngine.EventLoop, runtime *goja.Runtime) {
+	runtime.Set("done", func(value goja.Value) {
+		module.done <- value
+	})
+}
+
+func (module *doneModule) wait(t *testing.T) goja.Value {
+	t.Helper()
+	select {
+	case value := <-module.done:
+		return value
+	case <-time.After(5 * time.Second):
+		t.Fatal("script did not call done()")
+	}
+	return nil
+}
+
+func testScript(t *testing.T, scriptBody string) {
 	script := jsEngine.NewScript("test", scriptBody)
 
-	rootContext := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+	rootContext := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithShutdownSignals()) // ctrl+c gracefully shutdowns context
 
-	eventLoop := jsEngine.NewEventLoop(goja.New(), []jsEngine.Script{script})
+	done := newDoneModule()
+	eventLoop := jsEngine.NewEventLoop(goja.New(), []jsEngine.Script{script, jsEngine.NewScript("done", "done()")})
 
 	eventLoop.Import(jsEngine.Console{})
 	eventLoop.Import(testModule{})
+	eventLoop.Import(done)
 
 	rootContext.NewContextFor(eventLoop, "jsEngine", "eventLoop")
 
-	{ // handle ctrl+c for gracefully shutdown using context
-		ctrlC := make(chan os.Signal, 1)
-		signal.Notify(ctrlC, os.Interrupt)
-		go func() {
-			<-ctrlC
-			rootContext.Log(2, "CTRL+C signal")
-			rootContext.Cancel()
-		}()
-	}
-
-	{ // wait 1 second till exit
-		time.Sleep(time.Duration(timeoutSec) * time.Second)
-		rootContext.Log(2, "timeout")
-		rootContext.Cancel()
-	}
+	done.wait(t) // all scripts are executed
+	rootContext.Cancel()
 
 	rootContext.Wait()
 }
 
 func Test_CallHandler(t *testing.T) {
-	testScript(`
+	testScript(t, `
 	function handler(param1, param2) {
 		 Console.Log('handler executed '+param1+ ','+param2)
 
@@ -71,9 +84,9 @@ func Test_CallHandler(t *testing.T) {
 	}
 
 	handle(handler,2,3)
-	`, 1)
+	`)
 }
 
 func Test_ScriptException(t *testing.T) {
-	testScript("console.log(123)", 1)
+	testScript(t, "console.log(123)")
 }
@@ -12,3 +12,5 @@ require (
 	github.com/go-sourcemap/sourcemap v2.1.3+incompatible // indirect
 	golang.org/x/text v0.3.7 // indirect
 )
+
+replace github.com/mcfly722/goPackages/context => ../context
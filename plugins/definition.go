@@ -3,8 +3,6 @@ package plugins
 import (
 	"path/filepath"
 	"time"
-
-	"github.com/mcfly722/goPackages/context"
 )
 
 // PluginDefinition ...
@@ -19,7 +17,6 @@ type pluginDefinition struct {
 	id               string
 	modificationTime time.Time
 	manager          *manager
-	context          context.Context
 	body             string
 }
 
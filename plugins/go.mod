@@ -3,3 +3,5 @@ module github.com/mcfly722/goPackages/plugins
 go 1.13
 
 require github.com/mcfly722/goPackages/context v0.0.0-20220626115626-62f598e6d986
+
+replace github.com/mcfly722/goPackages/context => ../context
@@ -14,10 +14,13 @@ type manager struct {
 	provider          Provider
 	rescanIntervalSec int
 	constructor       Constructor
-	definitions       map[string]*pluginDefinition
+	definitions       map[string]*pluginDefinition // definitions stay registered after plugin closed itself, till plugin file is changed or removed
 	ready             sync.Mutex
 }
 
+// definition is stored as a value of plugin context, so manager finds plugin context among its childs
+type definitionKey struct{}
+
 // NewPluginsManager ...1
 func NewPluginsManager(provider Provider, rescanIntervalSec int, constructor Constructor) context.ContextedInstance {
 	return &manager{
@@ -29,10 +32,8 @@ func NewPluginsManager(provider Provider, rescanIntervalSec int, constructor Con
 }
 
 func (manager *manager) definitionIsOutdated(definition *pluginDefinition) bool {
-	manager.ready.Lock()
-	defer manager.ready.Unlock()
-	if cachedDefinition, ok := manager.definitions[definition.id]; ok {
-		if cachedDefinition.modificationTime == definition.modificationTime {
+	if registeredDefinition, ok := manager.getRegisteredDefinition(definition.id); ok {
+		if registeredDefinition.modificationTime == definition.modificationTime {
 			return false
 		}
 	}
@@ -62,6 +63,16 @@ func (manager *manager) unregisterPluginDefinition(definitionID string) *pluginD
 	return nil
 }
 
+// plugin context is nil if plugin is already closed
+func (manager *manager) getPluginContext(current context.Context, definition *pluginDefinition) context.Context {
+	for _, child := range current.Children() {
+		if child.Value(definitionKey{}) == definition {
+			return child
+		}
+	}
+	return nil
+}
+
 func (manager *manager) getResource(path string) (*[]byte, error) {
 	return manager.provider.GetResource(path)
 }
@@ -69,11 +80,12 @@ func (manager *manager) getResource(path string) (*[]byte, error) {
 // Go ...
 func (manager *manager) Go(current context.Context) {
 	current.Log(101, "loop started")
+
 	duration := time.Duration(0) // first interval is zero, because we need to start immediately
 loop:
 	for {
 		select {
-		case <-time.After(duration): // we do not use Ticker here because it can't start immediately, always need to wait interval
+		case <-current.Clock().After(duration): // we do not use Ticker here because it can't start immediately, always need to wait interval
 			{ // rescan for not loaded yet plugins
 				duration = time.Duration(manager.rescanIntervalSec) * time.Second // after first start we change interval dutation to seconds
 
@@ -119,7 +131,9 @@ loop:
 
 					for _, definitionForDeleting := range definitionsForDeleting {
 						unregisteredDefinition := manager.unregisterPluginDefinition(definitionForDeleting)
-						unregisteredDefinition.context.Cancel()
+						if pluginContext := manager.getPluginContext(current, unregisteredDefinition); pluginContext != nil {
+							pluginContext.Cancel()
+						}
 					}
 
 					current.Log(110, "delete not existing or outdated definitions", "done")
@@ -147,9 +161,10 @@ loop:
 								pluginInstance := manager.constructor(definition)
 
 								current.Log(110, "load new definitions", "NewContextFor")
-								definition.context, err = current.NewContextFor(pluginInstance, definition.Name(), "definition")
+								pluginContext, err := current.NewContextFor(pluginInstance, definition.Name(), "definition")
 								if err == nil {
 									current.Log(110, "load new definitions", "registerNewPluginDefinition", "...")
+									pluginContext.WithValue(definitionKey{}, definition)
 									manager.registerNewPluginDefinition(definition)
 									current.Log(110, "load new definitions", "registerNewPluginDefinition", "done")
 								} else {
@@ -0,0 +1,99 @@
+package plugins_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+	"github.com/mcfly722/goPackages/plugins"
+)
+
+type fakeProvider struct {
+	modificationTime time.Time
+	ready            sync.Mutex
+}
+
+func (provider *fakeProvider) GetPlugins() ([]string, error) {
+	return []string{"plugin.js"}, nil
+}
+
+func (provider *fakeProvider) GetPluginModificationTime(pluginPath string) (time.Time, error) {
+	provider.ready.Lock()
+	defer provider.ready.Unlock()
+	return provider.modificationTime, nil
+}
+
+func (provider *fakeProvider) GetResource(path string) (*[]byte, error) {
+	body := []byte("body")
+	return &body, nil
+}
+
+func (provider *fakeProvider) touch() {
+	provider.ready.Lock()
+	provider.modificationTime = provider.modificationTime.Add(time.Second)
+	provider.ready.Unlock()
+}
+
+// selfCancelingPlugin closes itself immediately after start
+type selfCancelingPlugin struct {
+	closed chan struct{}
+}
+
+func (plugin *selfCancelingPlugin) Go(current context.Context) {
+	current.Cancel()
+	<-current.Opened()
+	close(plugin.closed)
+}
+
+func Test_SelfCanceledPluginIsNotReloaded(t *testing.T) {
+	clock := context.NewFakeClock(time.Now())
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+
+	provider := &fakeProvider{}
+	started := make(chan *selfCancelingPlugin, 10)
+
+	manager := plugins.NewPluginsManager(provider, 1, func(definition plugins.PluginDefinition) context.ContextedInstance {
+		plugin := &selfCancelingPlugin{closed: make(chan struct{})}
+		started <- plugin
+		return plugin
+	})
+
+	if _, err := root.NewContextFor(manager, "pluginsManager", "pluginsManager"); err != nil {
+		t.Fatal(err)
+	}
+
+	waitStarted := func() *selfCancelingPlugin {
+		t.Helper()
+		select {
+		case plugin := <-started:
+			return plugin
+		case <-time.After(5 * time.Second):
+			t.Fatal("plugin is not loaded")
+		}
+		return nil
+	}
+
+	plugin := waitStarted()
+	<-plugin.closed
+
+	for i := 0; i < 3; i++ { // rescans do not load plugin which closed itself
+		clock.WaitTimers(1)
+		clock.Advance(time.Second)
+	}
+	clock.WaitTimers(1)
+
+	select {
+	case <-started:
+		t.Fatal("self canceled plugin is loaded again without file changes")
+	default:
+	}
+
+	provider.touch()
+	clock.Advance(time.Second)
+
+	<-waitStarted().closed
+
+	root.Cancel()
+	root.Wait()
+}
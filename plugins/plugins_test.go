@@ -1,8 +1,6 @@
 package plugins_test
 
 import (
-	"os"
-	"os/signal"
 	"testing"
 	"time"
 
@@ -52,7 +50,7 @@ loop:
 func Test_AsServer(t *testing.T) {
 	pluginsPath := ""
 
-	rootCtx := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+	rootCtx := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithShutdownSignals()) // ctrl+c gracefully shutdowns context
 
 	pluginsProvider := plugins.NewPluginsFromFilesProvider(pluginsPath, "*.go")
 
@@ -60,16 +58,6 @@ func Test_AsServer(t *testing.T) {
 
 	rootCtx.NewContextFor(pluginsManager, "pluginsManager", "pluginsManager")
 
-	{ // handle ctrl+c for gracefully shutdown using context
-		c := make(chan os.Signal, 1)
-		signal.Notify(c, os.Interrupt)
-		go func() {
-			<-c
-			rootCtx.Log(1, "CTRL+C signal")
-			rootCtx.Cancel()
-		}()
-	}
-
 	rootCtx.Wait()
 
 	rootCtx.Log(0, "done")
@@ -0,0 +1,161 @@
+package scheduler_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+	"github.com/mcfly722/goPackages/scheduler"
+)
+
+// sliceScheduler is previous implementation with sorted slice, it is kept only to compare benchmarks
+type sliceScheduler struct {
+	queue []*sliceTimer
+	clock context.Clock
+	ready sync.Mutex
+}
+
+type sliceTimer struct {
+	deadline time.Time
+	object   interface{}
+}
+
+func (scheduler *sliceScheduler) RegisterNewTimer(deadline time.Time, object scheduler.Object) {
+	newTimer := &sliceTimer{deadline: deadline, object: object}
+
+	scheduler.ready.Lock()
+	defer scheduler.ready.Unlock()
+
+	insertionPos := len(scheduler.queue)
+	for ; insertionPos > 0; insertionPos-- {
+		if scheduler.queue[insertionPos-1].deadline.Before(deadline) {
+			break
+		}
+	}
+
+	scheduler.queue = append(scheduler.queue[:insertionPos], append([]*sliceTimer{newTimer}, scheduler.queue[insertionPos:]...)...)
+}
+
+// newFilledSliceScheduler sorts all deadlines at once, sorted insertion of 100000 timers one by one takes minutes
+func newFilledSliceScheduler(deadlines []time.Time) *sliceScheduler {
+	scheduler := &sliceScheduler{clock: benchmarkClock(), queue: make([]*sliceTimer, len(deadlines))}
+	for i, deadline := range deadlines {
+		scheduler.queue[i] = &sliceTimer{deadline: deadline, object: i}
+	}
+	sort.SliceStable(scheduler.queue, func(i int, j int) bool {
+		return scheduler.queue[i].deadline.Before(scheduler.queue[j].deadline)
+	})
+	return scheduler
+}
+
+func (scheduler *sliceScheduler) TakeFirstOutdatedOrNil() scheduler.Object {
+	scheduler.ready.Lock()
+	defer scheduler.ready.Unlock()
+
+	if len(scheduler.queue) == 0 || scheduler.queue[0].deadline.After(scheduler.clock.Now()) {
+		return nil
+	}
+
+	object := scheduler.queue[0].object
+	scheduler.queue = scheduler.queue[1:]
+	return object
+}
+
+func (scheduler *sliceScheduler) CancelTimerFor(object scheduler.Object) {
+	scheduler.ready.Lock()
+	defer scheduler.ready.Unlock()
+
+	for {
+		found := -1
+		for i, timer := range scheduler.queue {
+			if timer.object == object {
+				found = i
+				break
+			}
+		}
+		if found == -1 {
+			return
+		}
+		scheduler.queue = append(scheduler.queue[:found], scheduler.queue[found+1:]...)
+	}
+}
+
+var benchmarkSizes = []int{1000, 10000, 100000}
+
+// all deadlines are outdated for this clock
+func benchmarkClock() *context.FakeClock {
+	clock := newFakeClock()
+	clock.Advance(24 * time.Hour)
+	return clock
+}
+
+func randomDeadlines(count int) []time.Time {
+	random := rand.New(rand.NewSource(1))
+	start := newFakeClock().Now()
+	deadlines := make([]time.Time, count)
+	for i := range deadlines {
+		deadlines[i] = start.Add(time.Duration(random.Int63n(int64(time.Hour))))
+	}
+	return deadlines
+}
+
+// register one more timer and take the first one, so queue size stays the same
+func Benchmark_RegisterAndTake(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		deadlines := randomDeadlines(size)
+
+		b.Run(fmt.Sprintf("heap/%v", size), func(b *testing.B) {
+			scheduler := scheduler.NewSchedulerWithClock(benchmarkClock())
+			for i := 0; i < size; i++ {
+				scheduler.RegisterNewTimer(deadlines[i], i)
+			}
+			next := randomDeadlines(b.N)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				scheduler.RegisterNewTimer(next[i], i)
+				scheduler.TakeFirstOutdatedOrNil()
+			}
+		})
+
+		b.Run(fmt.Sprintf("slice/%v", size), func(b *testing.B) {
+			scheduler := newFilledSliceScheduler(deadlines)
+			next := randomDeadlines(b.N)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				scheduler.RegisterNewTimer(next[i], i)
+				scheduler.TakeFirstOutdatedOrNil()
+			}
+		})
+	}
+}
+
+// register one more timer and cancel it, heap cancels by handle, slice could cancel only by object
+func Benchmark_RegisterAndCancel(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		deadlines := randomDeadlines(size)
+
+		b.Run(fmt.Sprintf("heap/%v", size), func(b *testing.B) {
+			scheduler := scheduler.NewSchedulerWithClock(benchmarkClock())
+			for i := 0; i < size; i++ {
+				scheduler.RegisterNewTimer(deadlines[i], i)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				scheduler.RegisterNewTimer(deadlines[i%size], -1).Cancel()
+			}
+		})
+
+		b.Run(fmt.Sprintf("slice/%v", size), func(b *testing.B) {
+			scheduler := newFilledSliceScheduler(deadlines)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				scheduler.RegisterNewTimer(deadlines[i%size], -1)
+				scheduler.CancelTimerFor(-1)
+			}
+		})
+	}
+}
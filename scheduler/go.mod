@@ -1,3 +1,7 @@
 module github.com/mcfly722/goPackages/scheduler
 
 go 1.17
+
+require github.com/mcfly722/goPackages/context v0.0.0-20220626121949-38712136951f
+
+replace github.com/mcfly722/goPackages/context => ../context
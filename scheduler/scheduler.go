@@ -1,8 +1,11 @@
 package scheduler
 
 import (
+	"container/heap"
 	"sync"
 	"time"
+
+	"github.com/mcfly722/goPackages/context"
 )
 
 // Object ...
@@ -10,127 +13,182 @@ type Object interface{} // it would be nice to use generics to limit types for s
 
 // Scheduler ...
 type Scheduler interface {
-	RegisterNewTimer(deadline time.Time, object Object)
+	RegisterNewTimer(deadline time.Time, object Object) TimerHandle
 	TakeFirstOutdatedOrNil() Object
 	CancelTimerFor(Object)
 	CancelAllTimers()
+	WaitNext(current context.Context) (Object, error) // blocks till the first timer is outdated and takes it, returns error when context is closing
+}
+
+// TimerHandle ...
+type TimerHandle interface {
+	Cancel()                          // removes only this timer from queue
+	Reset(newDeadline time.Time) bool // moves timer to new deadline (timer which is already taken or canceled is registered again), returns true if timer was active
+	Deadline() time.Time              // current deadline of timer
+	Active() bool                     // true till timer is not taken or canceled
 }
 
 // NewScheduler ...
 func NewScheduler() Scheduler {
+	return NewSchedulerWithClock(context.NewRealClock())
+}
+
+// NewSchedulerWithClock creates scheduler which checks deadlines with specified clock instead of real time (for example, with clock of context tree)
+func NewSchedulerWithClock(clock context.Clock) Scheduler {
 	return &scheduler{
-		queue: []*timer{},
+		queue: timersQueue{},
+		clock: clock,
 	}
 }
 
 // Scheduler ...
 type scheduler struct {
-	queue []*timer
-	ready sync.Mutex
+	queue        timersQueue
+	nextSequence uint64
+	clock        context.Clock
+	changed      chan struct{} // nil till somebody waits, it is closed and reset when queue changes, so waiters recheck the first deadline
+	ready        sync.Mutex
 }
 
-type timer struct {
-	deadline time.Time
-	object   interface{}
-}
-
-// RegisterNewTimer ...
-func (scheduler *scheduler) RegisterNewTimer(deadline time.Time, object Object) {
+// RegisterNewTimer adds timer for O(log(n)), returned handle cancels only this timer
+func (scheduler *scheduler) RegisterNewTimer(deadline time.Time, object Object) TimerHandle {
 
 	newTimer := &timer{
-		deadline: deadline,
-		object:   object,
+		scheduler: scheduler,
+		deadline:  deadline,
+		object:    object,
 	}
 
 	scheduler.ready.Lock()
 	defer scheduler.ready.Unlock()
 
-	// first insert
-	if len(scheduler.queue) == 0 {
-		scheduler.queue = append(scheduler.queue, newTimer)
-		return
-	}
+	scheduler.push(newTimer)
+
+	return newTimer
+}
 
-	// find and insert timer in correct place in queue (first element it is nearest deadline)
-	// Number of operations = O(n) where n-current number of elements in sorted slice. For big slices this part could be optimized to log(n).
+func (scheduler *scheduler) push(timer *timer) {
+	timer.sequence = scheduler.nextSequence
+	scheduler.nextSequence++
 
-	insertionPos := len(scheduler.queue)
-	for ; insertionPos > 0; insertionPos-- {
-		if insertionPos > 0 {
-			if scheduler.queue[insertionPos-1].deadline.Before(deadline) {
-				break
-			}
-		}
-	}
+	heap.Push(&scheduler.queue, timer)
+	scheduler.notifyChanged()
+}
 
-	if insertionPos == 0 {
-		scheduler.queue = append([]*timer{newTimer}, scheduler.queue...)
-		return
+// must be called under ready lock
+func (scheduler *scheduler) notifyChanged() {
+	if scheduler.changed != nil {
+		close(scheduler.changed)
+		scheduler.changed = nil
 	}
+}
 
-	if insertionPos == len(scheduler.queue) {
-		scheduler.queue = append(scheduler.queue, newTimer)
-		return
+// must be called under ready lock, channel is created only for waiters, so scheduler without waiters does not allocate on queue changes
+func (scheduler *scheduler) waitChanged() <-chan struct{} {
+	if scheduler.changed == nil {
+		scheduler.changed = make(chan struct{})
 	}
-
-	scheduler.queue = append(scheduler.queue[:insertionPos+1], scheduler.queue[insertionPos:]...)
-	scheduler.queue[insertionPos] = newTimer
+	return scheduler.changed
 }
 
-// AsyncTakeFirstOutdated ...
+// TakeFirstOutdatedOrNil ...
 func (scheduler *scheduler) TakeFirstOutdatedOrNil() Object {
 
 	scheduler.ready.Lock()
 	defer scheduler.ready.Unlock()
 
+	return scheduler.takeFirstOutdatedOrNil()
+}
+
+// must be called under ready lock, taken timer could only make the first deadline later, so waiters are not notified
+func (scheduler *scheduler) takeFirstOutdatedOrNil() Object {
 	if len(scheduler.queue) == 0 {
 		return nil // no deadlines in queue
 	}
 
-	if scheduler.queue[0].deadline.After(time.Now()) {
+	if scheduler.queue[0].deadline.After(scheduler.clock.Now()) {
 		return nil // nearest deadline not outdated
 	}
 
-	// first deadline outdated
-	object := scheduler.queue[0].object
-
-	scheduler.queue = scheduler.queue[1:] // remove outdated element from queue
-
-	return object
+	// first deadline outdated, remove it from queue
+	return heap.Pop(&scheduler.queue).(*timer).object
 }
 
+// CancelTimerFor removes all timers of object with single pass over queue
 func (scheduler *scheduler) CancelTimerFor(object Object) {
 	scheduler.ready.Lock()
 	defer scheduler.ready.Unlock()
 
-	// This deletion several objects from schedule.queue is not optimized enough.
-	// There is another way to delete all objects for single pass, but need additional accuracy.
-	for {
-		lastFoundedObjectPosition := -1
-
-		// find first occurence
-	nextPass:
-		for i, timer := range scheduler.queue {
-			if timer.object == object {
-				lastFoundedObjectPosition = i
-				break nextPass
-			}
-		}
-
-		// if no more object instances found in queue we just exit
-		if lastFoundedObjectPosition == -1 {
-			break
+	kept := scheduler.queue[:0]
+	for _, timer := range scheduler.queue {
+		if timer.object == object {
+			timer.index = -1
 		} else {
-			// deleting object from queue in position lastFoundedObjectIndex
-			scheduler.queue = append(scheduler.queue[:lastFoundedObjectPosition], scheduler.queue[lastFoundedObjectPosition+1:]...)
+			timer.index = len(kept)
+			kept = append(kept, timer)
 		}
 	}
+	for i := len(kept); i < len(scheduler.queue); i++ {
+		scheduler.queue[i] = nil
+	}
+	scheduler.queue = kept
 
+	heap.Init(&scheduler.queue)
+	scheduler.notifyChanged()
 }
 
 // CancelAllTimers ...
 func (scheduler *scheduler) CancelAllTimers() {
 	scheduler.ready.Lock()
-	scheduler.queue = []*timer{}
+	for _, timer := range scheduler.queue {
+		timer.index = -1
+	}
+	scheduler.queue = timersQueue{}
+	scheduler.notifyChanged()
 	scheduler.ready.Unlock()
 }
+
+// Cancel removes timer from queue for O(log(n)), it does nothing if timer is already taken or canceled
+func (timer *timer) Cancel() {
+	timer.scheduler.ready.Lock()
+	defer timer.scheduler.ready.Unlock()
+
+	if timer.index >= 0 {
+		heap.Remove(&timer.scheduler.queue, timer.index)
+		timer.scheduler.notifyChanged()
+	}
+}
+
+// Reset changes deadline for O(log(n)), timer is ordered with timers of same deadline as newly registered one
+func (timer *timer) Reset(newDeadline time.Time) bool {
+	timer.scheduler.ready.Lock()
+	defer timer.scheduler.ready.Unlock()
+
+	timer.deadline = newDeadline
+
+	if timer.index < 0 {
+		timer.scheduler.push(timer)
+		return false
+	}
+
+	timer.sequence = timer.scheduler.nextSequence
+	timer.scheduler.nextSequence++
+	heap.Fix(&timer.scheduler.queue, timer.index)
+	timer.scheduler.notifyChanged()
+
+	return true
+}
+
+// Deadline ...
+func (timer *timer) Deadline() time.Time {
+	timer.scheduler.ready.Lock()
+	defer timer.scheduler.ready.Unlock()
+	return timer.deadline
+}
+
+// Active ...
+func (timer *timer) Active() bool {
+	timer.scheduler.ready.Lock()
+	defer timer.scheduler.ready.Unlock()
+	return timer.index >= 0
+}
@@ -8,22 +8,28 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mcfly722/goPackages/context"
 	"github.com/mcfly722/goPackages/scheduler"
 )
 
+func newFakeClock() *context.FakeClock {
+	return context.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
 // TakeFirstOutdated ...
-func getFirstOutdatedWithWaiting(scheduler scheduler.Scheduler) interface{} {
+func getFirstOutdatedWithWaiting(scheduler scheduler.Scheduler, clock *context.FakeClock) interface{} {
 	for {
 		object := scheduler.TakeFirstOutdatedOrNil()
 		if object != nil {
 			return object
 		}
+		clock.Advance(time.Nanosecond)
 	}
 }
 
-func testQueueWithLenght(t *testing.T, scheduler scheduler.Scheduler, sequence *[]int) {
+func testQueueWithLenght(t *testing.T, scheduler scheduler.Scheduler, clock *context.FakeClock, sequence *[]int) {
 
-	now := time.Now()
+	now := clock.Now()
 
 	var wg sync.WaitGroup
 	for i := 0; i < len(*sequence); i++ {
@@ -40,7 +46,7 @@ func testQueueWithLenght(t *testing.T, scheduler scheduler.Scheduler, sequence *
 	result := []int{}
 
 	for i := 0; i < len(*sequence); i++ {
-		a := getFirstOutdatedWithWaiting(scheduler)
+		a := getFirstOutdatedWithWaiting(scheduler, clock)
 		result = append(result, a.(int))
 	}
 
@@ -52,9 +58,10 @@ func testQueueWithLenght(t *testing.T, scheduler scheduler.Scheduler, sequence *
 }
 
 func Test_First(t *testing.T) {
-	scheduler := scheduler.NewScheduler()
-	scheduler.RegisterNewTimer(time.Now(), 1)
-	obj := getFirstOutdatedWithWaiting(scheduler)
+	clock := newFakeClock()
+	scheduler := scheduler.NewSchedulerWithClock(clock)
+	scheduler.RegisterNewTimer(clock.Now(), 1)
+	obj := getFirstOutdatedWithWaiting(scheduler, clock)
 	if obj == nil {
 		t.Fatal("no outdated objects")
 	}
@@ -117,10 +124,139 @@ func string2Combination(str string) *[]int {
 }
 
 func Test_RecombineFirstN(t *testing.T) {
-	scheduler := scheduler.NewScheduler()
+	clock := newFakeClock()
+	scheduler := scheduler.NewSchedulerWithClock(clock)
 	for i := 1; i < 1024; i++ {
 		str := strconv.FormatInt(int64(i), 4)
 		sequence := string2Combination(str)
-		testQueueWithLenght(t, scheduler, sequence)
+		testQueueWithLenght(t, scheduler, clock, sequence)
+	}
+}
+
+func Test_NotOutdated(t *testing.T) {
+	clock := newFakeClock()
+	scheduler := scheduler.NewSchedulerWithClock(clock)
+	scheduler.RegisterNewTimer(clock.Now().Add(time.Hour), 1)
+
+	if object := scheduler.TakeFirstOutdatedOrNil(); object != nil {
+		t.Fatal("scheduler returns object before its deadline")
+	}
+
+	clock.Advance(time.Hour)
+
+	if object := scheduler.TakeFirstOutdatedOrNil(); object != 1 {
+		t.Fatalf("scheduler returns %v instead of outdated object", object)
+	}
+}
+
+func Test_CancelByHandle(t *testing.T) {
+	clock := newFakeClock()
+	scheduler := scheduler.NewSchedulerWithClock(clock)
+	scheduler.RegisterNewTimer(clock.Now(), 1)
+	handle := scheduler.RegisterNewTimer(clock.Now(), 1)
+	scheduler.RegisterNewTimer(clock.Now(), 2)
+
+	handle.Cancel()
+	handle.Cancel() // second call does nothing
+
+	result := []interface{}{scheduler.TakeFirstOutdatedOrNil(), scheduler.TakeFirstOutdatedOrNil(), scheduler.TakeFirstOutdatedOrNil()}
+	if result[0] != 1 || result[1] != 2 || result[2] != nil {
+		t.Fatalf("unexpected objects %v after canceling one of timers", result)
+	}
+}
+
+func Test_SameDeadlineOrder(t *testing.T) {
+	clock := newFakeClock()
+	scheduler := scheduler.NewSchedulerWithClock(clock)
+	for i := 0; i < 100; i++ {
+		scheduler.RegisterNewTimer(clock.Now(), i)
+	}
+
+	for i := 0; i < 100; i++ {
+		if object := scheduler.TakeFirstOutdatedOrNil(); object != i {
+			t.Fatalf("timer %v taken instead of %v", object, i)
+		}
+	}
+}
+
+func Test_IndependentTimersOfObject(t *testing.T) {
+	clock := newFakeClock()
+	scheduler := scheduler.NewSchedulerWithClock(clock)
+	first := scheduler.RegisterNewTimer(clock.Now().Add(time.Second), 1)
+	second := scheduler.RegisterNewTimer(clock.Now().Add(2*time.Second), 1)
+
+	first.Cancel()
+	if first.Active() || !second.Active() {
+		t.Fatal("canceling one timer affects another timer of same object")
+	}
+
+	clock.Advance(2 * time.Second)
+
+	if object := scheduler.TakeFirstOutdatedOrNil(); object != 1 || second.Active() {
+		t.Fatalf("second timer is not taken, object=%v", object)
+	}
+	if object := scheduler.TakeFirstOutdatedOrNil(); object != nil {
+		t.Fatalf("canceled timer is taken, object=%v", object)
+	}
+}
+
+func Test_ResetPushesDeadlineBack(t *testing.T) {
+	clock := newFakeClock()
+	scheduler := scheduler.NewSchedulerWithClock(clock)
+	keepalive := scheduler.RegisterNewTimer(clock.Now().Add(time.Second), "keepalive")
+	scheduler.RegisterNewTimer(clock.Now().Add(2*time.Second), "other")
+
+	if !keepalive.Reset(clock.Now().Add(3 * time.Second)) {
+		t.Fatal("active timer is reported as inactive")
+	}
+	if !keepalive.Deadline().Equal(clock.Now().Add(3 * time.Second)) {
+		t.Fatalf("deadline is not changed: %v", keepalive.Deadline())
+	}
+
+	clock.Advance(2 * time.Second)
+
+	if object := scheduler.TakeFirstOutdatedOrNil(); object != "other" {
+		t.Fatalf("unexpected object %v", object)
+	}
+	if object := scheduler.TakeFirstOutdatedOrNil(); object != nil {
+		t.Fatalf("timer is taken before new deadline, object=%v", object)
+	}
+
+	clock.Advance(time.Second)
+
+	if object := scheduler.TakeFirstOutdatedOrNil(); object != "keepalive" {
+		t.Fatalf("unexpected object %v", object)
+	}
+}
+
+func Test_ResetInactive(t *testing.T) {
+	clock := newFakeClock()
+	scheduler := scheduler.NewSchedulerWithClock(clock)
+	handle := scheduler.RegisterNewTimer(clock.Now(), 1)
+	handle.Cancel()
+
+	if handle.Reset(clock.Now()) {
+		t.Fatal("canceled timer is reported as active")
+	}
+	if !handle.Active() {
+		t.Fatal("timer is not registered again")
+	}
+	if object := scheduler.TakeFirstOutdatedOrNil(); object != 1 {
+		t.Fatalf("unexpected object %v", object)
+	}
+}
+
+func Test_NoAllocationsWithoutWaiters(t *testing.T) {
+	clock := newFakeClock()
+	scheduler := scheduler.NewSchedulerWithClock(clock)
+	handle := scheduler.RegisterNewTimer(clock.Now(), 1)
+
+	allocations := testing.AllocsPerRun(100, func() {
+		handle.Reset(clock.Now().Add(time.Second))
+		handle.Cancel()
+	})
+
+	if allocations != 0 {
+		t.Fatalf("queue changes allocated %v times without waiters", allocations)
 	}
 }
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"time"
+)
+
+type timer struct {
+	scheduler *scheduler
+	deadline  time.Time
+	object    Object
+	sequence  uint64 // timers with same deadline are taken in order of registration
+	index     int    // position in queue, -1 when timer is taken or canceled
+}
+
+// timersQueue is a binary min-heap by deadline, every timer knows its index, so it could be removed from the middle for O(log(n))
+type timersQueue []*timer
+
+func (queue timersQueue) Len() int {
+	return len(queue)
+}
+
+func (queue timersQueue) Less(i int, j int) bool {
+	if queue[i].deadline.Equal(queue[j].deadline) {
+		return queue[i].sequence < queue[j].sequence
+	}
+	return queue[i].deadline.Before(queue[j].deadline)
+}
+
+func (queue timersQueue) Swap(i int, j int) {
+	queue[i], queue[j] = queue[j], queue[i]
+	queue[i].index = i
+	queue[j].index = j
+}
+
+func (queue *timersQueue) Push(element interface{}) {
+	timer := element.(*timer)
+	timer.index = len(*queue)
+	*queue = append(*queue, timer)
+}
+
+func (queue *timersQueue) Pop() interface{} {
+	old := *queue
+	last := len(old) - 1
+	timer := old[last]
+	old[last] = nil // timer could be referenced only by its handle
+	timer.index = -1
+	*queue = old[:last]
+	return timer
+}
@@ -0,0 +1,41 @@
+package scheduler
+
+import (
+	"github.com/mcfly722/goPackages/context"
+)
+
+// WaitNext sleeps till the first deadline (or till queue changes) instead of polling TakeFirstOutdatedOrNil() in a loop
+func (scheduler *scheduler) WaitNext(current context.Context) (Object, error) {
+	for {
+		scheduler.ready.Lock()
+		object := scheduler.takeFirstOutdatedOrNil()
+		if object != nil {
+			scheduler.ready.Unlock()
+			return object, nil
+		}
+
+		changed := scheduler.waitChanged()
+		var timer context.ClockTimer
+		var outdated chan struct{} // nil channel blocks forever if queue is empty
+		if len(scheduler.queue) > 0 {
+			outdated = make(chan struct{})
+			timer = scheduler.clock.AfterFunc(scheduler.queue[0].deadline.Sub(scheduler.clock.Now()), func() { close(outdated) })
+		}
+		scheduler.ready.Unlock()
+
+		select {
+		case <-current.Canceled(): // wakes as soon as cancellation starts, Opened() is closed only after all childs are closed
+		case <-current.Opened():
+		case <-changed:
+		case <-outdated:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+
+		if err := current.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
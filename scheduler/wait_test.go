@@ -0,0 +1,167 @@
+package scheduler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcfly722/goPackages/context"
+	"github.com/mcfly722/goPackages/scheduler"
+)
+
+type waiter struct {
+	scheduler scheduler.Scheduler
+	results   chan interface{}
+}
+
+func (waiter *waiter) Go(current context.Context) {
+	for {
+		object, err := waiter.scheduler.WaitNext(current)
+		if err != nil {
+			waiter.results <- err
+			<-current.Opened()
+			return
+		}
+		waiter.results <- object
+	}
+}
+
+func startWaiter(t *testing.T, clock *context.FakeClock) (context.RootContext, scheduler.Scheduler, chan interface{}) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true), context.WithClock(clock))
+	waiter := &waiter{
+		scheduler: scheduler.NewSchedulerWithClock(root.Clock()),
+		results:   make(chan interface{}, 10),
+	}
+	if _, err := root.NewContextFor(waiter, "waiter", "waiter"); err != nil {
+		t.Fatal(err)
+	}
+	return root, waiter.scheduler, waiter.results
+}
+
+func expectResult(t *testing.T, results chan interface{}) interface{} {
+	t.Helper()
+	select {
+	case result := <-results:
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitNext() is not returned")
+	}
+	return nil
+}
+
+func expectNoResult(t *testing.T, results chan interface{}) {
+	t.Helper()
+	select {
+	case result := <-results:
+		t.Fatalf("WaitNext() returned %v before deadline", result)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func Test_WaitNext(t *testing.T) {
+	clock := newFakeClock()
+	root, scheduler, results := startWaiter(t, clock)
+
+	scheduler.RegisterNewTimer(clock.Now().Add(time.Hour), 1)
+	clock.WaitTimers(1)
+	expectNoResult(t, results)
+
+	clock.Advance(time.Hour)
+
+	if result := expectResult(t, results); result != 1 {
+		t.Fatalf("unexpected result %v", result)
+	}
+
+	root.Cancel()
+	root.Wait()
+}
+
+func Test_WaitNextEarlierTimer(t *testing.T) {
+	clock := newFakeClock()
+	root, scheduler, results := startWaiter(t, clock)
+
+	scheduler.RegisterNewTimer(clock.Now().Add(time.Hour), "later")
+	clock.WaitTimers(1)
+	scheduler.RegisterNewTimer(clock.Now().Add(time.Second), "earlier")
+
+	clock.Advance(time.Second)
+
+	if result := expectResult(t, results); result != "earlier" {
+		t.Fatalf("unexpected result %v", result)
+	}
+	expectNoResult(t, results)
+
+	root.Cancel()
+	root.Wait()
+}
+
+func Test_WaitNextCanceledTimer(t *testing.T) {
+	clock := newFakeClock()
+	root, scheduler, results := startWaiter(t, clock)
+
+	scheduler.RegisterNewTimer(clock.Now().Add(time.Hour), "later")
+	first := scheduler.RegisterNewTimer(clock.Now().Add(time.Second), "canceled")
+	clock.WaitTimers(1)
+	first.Cancel()
+
+	clock.Advance(time.Second)
+	expectNoResult(t, results)
+
+	clock.Advance(time.Hour)
+
+	if result := expectResult(t, results); result != "later" {
+		t.Fatalf("unexpected result %v", result)
+	}
+
+	root.Cancel()
+	root.Wait()
+}
+
+func Test_WaitNextCancellation(t *testing.T) {
+	root, _, results := startWaiter(t, newFakeClock())
+
+	expectNoResult(t, results)
+	root.Cancel()
+
+	if _, ok := expectResult(t, results).(*context.CanceledError); !ok {
+		t.Fatal("WaitNext() did not return CanceledError")
+	}
+
+	root.Wait()
+}
+
+// blockingNode does not exit till it is released, so its parent Opened() channel is not closed
+type blockingNode struct {
+	release chan struct{}
+}
+
+func (node *blockingNode) Go(current context.Context) {
+	<-current.Opened()
+	<-node.release
+}
+
+func Test_WaitNextCancellationWithClosingChild(t *testing.T) {
+	root := context.NewRootContext(context.NewConsoleLogDebugger(100, true))
+	waiter := &waiter{
+		scheduler: scheduler.NewScheduler(),
+		results:   make(chan interface{}, 10),
+	}
+	ctx, err := root.NewContextFor(waiter, "waiter", "waiter")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocking := &blockingNode{release: make(chan struct{})}
+	if _, err := ctx.NewContextFor(blocking, "blocking", "node"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectNoResult(t, waiter.results)
+	root.Cancel()
+
+	if _, ok := expectResult(t, waiter.results).(*context.CanceledError); !ok {
+		t.Fatal("WaitNext() did not return CanceledError while child is closing")
+	}
+
+	close(blocking.release)
+	root.Wait()
+}